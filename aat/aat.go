@@ -0,0 +1,112 @@
+// Package aat issues the Application Authentication Tokens the gateway uses to relay on behalf of applications
+package aat
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/pokt-foundation/portal-api-go/repository"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// DefaultVersion is the AAT version used when the application has none
+	DefaultVersion = "0.0.1"
+
+	addressSize = 20
+)
+
+var (
+	ErrMissingClientPublicKey = errors.New("missing client public key")
+	ErrMissingPrivateKey      = errors.New("missing application private key")
+	ErrInvalidPrivateKey      = errors.New("invalid application private key")
+)
+
+// token is the AAT as signed by Pocket nodes, field names must match pocket-core
+type token struct {
+	Version              string `json:"version"`
+	ApplicationPublicKey string `json:"app_pub_key"`
+	ClientPublicKey      string `json:"client_pub_key"`
+	ApplicationSignature string `json:"signature"`
+}
+
+// Sign returns a GatewayAAT for the given client signed with the hex encoded application private key, the
+// application keeps its key pair and so its staked address, keys are never generated as they must be staked first
+func Sign(privateKey, clientPublicKey, version string) (*repository.GatewayAAT, error) {
+	if privateKey == "" {
+		return nil, ErrMissingPrivateKey
+	}
+
+	if clientPublicKey == "" {
+		return nil, ErrMissingClientPublicKey
+	}
+
+	if version == "" {
+		version = DefaultVersion
+	}
+
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey := key.Public().(ed25519.PublicKey)
+	applicationPublicKey := hex.EncodeToString(publicKey)
+
+	hash, err := Hash(version, applicationPublicKey, clientPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.GatewayAAT{
+		Address:              Address(publicKey),
+		ApplicationPublicKey: applicationPublicKey,
+		ApplicationSignature: hex.EncodeToString(ed25519.Sign(key, hash)),
+		ClientPublicKey:      clientPublicKey,
+		PrivateKey:           privateKey,
+		Version:              version,
+	}, nil
+}
+
+// parsePrivateKey decodes a hex encoded ed25519 private key, either in full or as its seed
+func parsePrivateKey(privateKey string) (ed25519.PrivateKey, error) {
+	rawKey, err := hex.DecodeString(privateKey)
+	if err != nil {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	switch len(rawKey) {
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(rawKey), nil
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(rawKey), nil
+	default:
+		return nil, ErrInvalidPrivateKey
+	}
+}
+
+// Hash returns the SHA3-256 hash of the unsigned AAT, which is what the application signs
+func Hash(version, applicationPublicKey, clientPublicKey string) ([]byte, error) {
+	rawToken, err := json.Marshal(token{
+		Version:              version,
+		ApplicationPublicKey: applicationPublicKey,
+		ClientPublicKey:      clientPublicKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha3.Sum256(rawToken)
+
+	return hash[:], nil
+}
+
+// Address returns the Pocket address of a public key, the first 20 bytes of its SHA-256 hash
+func Address(publicKey ed25519.PublicKey) string {
+	hash := sha256.Sum256(publicKey)
+
+	return hex.EncodeToString(hash[:addressSize])
+}
@@ -0,0 +1,56 @@
+package aat
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	c := require.New(t)
+
+	clientPublicKey := "ba2724be652eca0a350bc07ba2724be652eca0a350bc07ba2724be652eca0a35"
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	c.NoError(err)
+
+	rawPrivateKey := hex.EncodeToString(privateKey)
+
+	aat, err := Sign(rawPrivateKey, clientPublicKey, "")
+	c.NoError(err)
+	c.Equal(DefaultVersion, aat.Version)
+	c.Equal(clientPublicKey, aat.ClientPublicKey)
+	c.Equal(hex.EncodeToString(publicKey), aat.ApplicationPublicKey)
+	c.Equal(rawPrivateKey, aat.PrivateKey)
+	c.Equal(Address(publicKey), aat.Address)
+	c.Len(aat.Address, 2*addressSize)
+
+	signature, err := hex.DecodeString(aat.ApplicationSignature)
+	c.NoError(err)
+
+	hash, err := Hash(aat.Version, aat.ApplicationPublicKey, aat.ClientPublicKey)
+	c.NoError(err)
+	c.True(ed25519.Verify(publicKey, hash, signature))
+
+	// The application key pair is kept across versions and seeds sign as their full key
+	otherAAT, err := Sign(hex.EncodeToString(privateKey.Seed()), clientPublicKey, "0.0.2")
+	c.NoError(err)
+	c.Equal("0.0.2", otherAAT.Version)
+	c.Equal(aat.ApplicationPublicKey, otherAAT.ApplicationPublicKey)
+	c.Equal(aat.Address, otherAAT.Address)
+
+	_, err = Sign(rawPrivateKey, "", "")
+	c.ErrorIs(err, ErrMissingClientPublicKey)
+
+	_, err = Sign("", clientPublicKey, "")
+	c.ErrorIs(err, ErrMissingPrivateKey)
+
+	_, err = Sign("not_hex", clientPublicKey, "")
+	c.ErrorIs(err, ErrInvalidPrivateKey)
+
+	_, err = Sign("a2f1b3c4", clientPublicKey, "")
+	c.ErrorIs(err, ErrInvalidPrivateKey)
+}
@@ -1,18 +1,29 @@
 package cache
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"sort"
+	"strings"
 	"sync"
 
+	"github.com/pokt-foundation/pocket-http-db/types"
 	"github.com/pokt-foundation/portal-api-go/repository"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	errBlockchainAliasConflict = errors.New("blockchain alias already in use")
+)
+
 // Reader represents implementation of reader interface
 type Reader interface {
 	ReadApplications() ([]*repository.Application, error)
 	ReadBlockchains() ([]*repository.Blockchain, error)
 	ReadLoadBalancers() ([]*repository.LoadBalancer, error)
+	ReadLoadBalancerRemovals() ([]*types.LoadBalancerRemoval, error)
+	ReadLoadBalancerRemoval(id string) (*types.LoadBalancerRemoval, error)
 	ReadPayPlans() ([]*repository.PayPlan, error)
 	ReadRedirects() ([]*repository.Redirect, error)
 	NotificationChannel() <-chan *repository.Notification
@@ -24,15 +35,24 @@ type Cache struct {
 	rwMutex                    sync.RWMutex
 	applicationsMap            map[string]*repository.Application
 	applicationsMapByUserID    map[string][]*repository.Application
+	applicationsMapByPublicKey map[string]*repository.Application
 	applications               []*repository.Application
 	blockchainsMap             map[string]*repository.Blockchain
+	blockchainsMapByAlias      map[string]*repository.Blockchain
 	blockchains                []*repository.Blockchain
 	loadBalancersMap           map[string]*repository.LoadBalancer
 	loadBalancersMapByUserID   map[string][]*repository.LoadBalancer
 	loadBalancers              []*repository.LoadBalancer
+	loadBalancerRemovals       map[string]*types.LoadBalancerRemoval
 	payPlansMap                map[repository.PayPlanType]*repository.PayPlan
 	payPlans                   []*repository.PayPlan
 	redirectsMapByBlockchainID map[string][]*repository.Redirect
+	redirectsMapByDomain       map[string]*repository.Redirect
+
+	generation         uint64
+	collectionVersions map[Collection]Version
+	entityVersions     map[Collection]map[string]Version
+	removals           changeLog
 
 	listening bool
 
@@ -57,6 +77,9 @@ func NewCache(reader Reader, logger *logrus.Logger) *Cache {
 		pendingSyncCheckOptions:    make(map[string]repository.SyncCheckOptions),
 		pendingStickyOptions:       make(map[string]repository.StickyOptions),
 		pendingLbApps:              make(map[string][]repository.LbApp),
+		loadBalancerRemovals:       make(map[string]*types.LoadBalancerRemoval),
+		collectionVersions:         make(map[Collection]Version),
+		entityVersions:             make(map[Collection]map[string]Version),
 		log:                        logger,
 	}
 }
@@ -77,6 +100,58 @@ func (c *Cache) GetApplicationsByUserID(userID string) []*repository.Application
 	return c.applicationsMapByUserID[userID]
 }
 
+// GetApplicationsByIDs returns the Applications found in cache for the given IDs
+// along with the IDs that did not match any Application
+func (c *Cache) GetApplicationsByIDs(applicationIDs []string) ([]*repository.Application, []string) {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	apps := []*repository.Application{}
+	missing := []string{}
+
+	for _, applicationID := range applicationIDs {
+		app, ok := c.applicationsMap[applicationID]
+		if !ok {
+			missing = append(missing, applicationID)
+			continue
+		}
+
+		apps = append(apps, app)
+	}
+
+	return apps, missing
+}
+
+// GetApplicationByPublicKey returns Application from cache by its gateway AAT application public key
+func (c *Cache) GetApplicationByPublicKey(publicKey string) *repository.Application {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	return c.applicationsMapByPublicKey[publicKey]
+}
+
+// GetApplicationsByPublicKeys returns the Applications found in cache for the given
+// public keys along with the public keys that did not match any Application
+func (c *Cache) GetApplicationsByPublicKeys(publicKeys []string) ([]*repository.Application, []string) {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	apps := []*repository.Application{}
+	missing := []string{}
+
+	for _, publicKey := range publicKeys {
+		app, ok := c.applicationsMapByPublicKey[publicKey]
+		if !ok {
+			missing = append(missing, publicKey)
+			continue
+		}
+
+		apps = append(apps, app)
+	}
+
+	return apps, missing
+}
+
 // GetApplications returns all Applications in cache
 func (c *Cache) GetApplications() []*repository.Application {
 	c.rwMutex.RLock()
@@ -93,6 +168,14 @@ func (c *Cache) GetBlockchain(blockchainID string) *repository.Blockchain {
 	return c.blockchainsMap[blockchainID]
 }
 
+// GetBlockchainByAlias returns Blockchain from cache by any of its aliases, case insensitive
+func (c *Cache) GetBlockchainByAlias(alias string) *repository.Blockchain {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	return c.blockchainsMapByAlias[strings.ToLower(alias)]
+}
+
 // GetBlockchains returns all Blockchains from cache
 func (c *Cache) GetBlockchains() []*repository.Blockchain {
 	c.rwMutex.RLock()
@@ -109,6 +192,36 @@ func (c *Cache) GetLoadBalancer(loadBalancerID string) *repository.LoadBalancer
 	return c.loadBalancersMap[loadBalancerID]
 }
 
+// GetLoadBalancerRemoval returns the removal of the load balancer, nil when it is not removed
+func (c *Cache) GetLoadBalancerRemoval(loadBalancerID string) *types.LoadBalancerRemoval {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	return c.loadBalancerRemovals[loadBalancerID]
+}
+
+// GetLoadBalancersByIDs returns the Loadbalancers found in cache for the given IDs
+// along with the IDs that did not match any Loadbalancer
+func (c *Cache) GetLoadBalancersByIDs(loadBalancerIDs []string) ([]*repository.LoadBalancer, []string) {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	lbs := []*repository.LoadBalancer{}
+	missing := []string{}
+
+	for _, loadBalancerID := range loadBalancerIDs {
+		lb, ok := c.loadBalancersMap[loadBalancerID]
+		if !ok {
+			missing = append(missing, loadBalancerID)
+			continue
+		}
+
+		lbs = append(lbs, lb)
+	}
+
+	return lbs, missing
+}
+
 // GetLoadBalancers returns all Loadbalancers on cache
 func (c *Cache) GetLoadBalancers() []*repository.LoadBalancer {
 	c.rwMutex.RLock()
@@ -124,6 +237,35 @@ func (c *Cache) GetLoadBalancersByUserID(userID string) []*repository.LoadBalanc
 	return c.loadBalancersMapByUserID[userID]
 }
 
+// GetUserIDs returns the sorted IDs of the users owning at least one Application or LoadBalancer in cache
+func (c *Cache) GetUserIDs() []string {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	userIDs := make(map[string]bool)
+
+	for userID, apps := range c.applicationsMapByUserID {
+		if userID != "" && len(apps) > 0 {
+			userIDs[userID] = true
+		}
+	}
+
+	for userID, lbs := range c.loadBalancersMapByUserID {
+		if userID != "" && len(lbs) > 0 {
+			userIDs[userID] = true
+		}
+	}
+
+	sortedUserIDs := make([]string, 0, len(userIDs))
+	for userID := range userIDs {
+		sortedUserIDs = append(sortedUserIDs, userID)
+	}
+
+	sort.Strings(sortedUserIDs)
+
+	return sortedUserIDs
+}
+
 // GetPayPlan returns PayPlan from cache by planType
 func (c *Cache) GetPayPlan(planType repository.PayPlanType) *repository.PayPlan {
 	c.rwMutex.RLock()
@@ -148,6 +290,40 @@ func (c *Cache) GetRedirects(blockchainID string) []*repository.Redirect {
 	return c.redirectsMapByBlockchainID[blockchainID]
 }
 
+// GetRedirectByDomain returns Redirect from cache by domain, if there is no exact
+// match the wildcard redirects of its parent domains are checked (e.g. *.gateway.network)
+func (c *Cache) GetRedirectByDomain(domain string) *repository.Redirect {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	domain = normalizeDomain(domain)
+
+	if redirect, ok := c.redirectsMapByDomain[domain]; ok {
+		return redirect
+	}
+
+	labels := strings.Split(domain, ".")
+
+	for i := 1; i < len(labels)-1; i++ {
+		wildcard := "*." + strings.Join(labels[i:], ".")
+
+		if redirect, ok := c.redirectsMapByDomain[wildcard]; ok {
+			return redirect
+		}
+	}
+
+	return nil
+}
+
+// normalizeDomain lowercases the domain and removes port and trailing dot if present
+func normalizeDomain(domain string) string {
+	if host, _, err := net.SplitHostPort(domain); err == nil {
+		domain = host
+	}
+
+	return strings.ToLower(strings.TrimSuffix(domain, "."))
+}
+
 func (c *Cache) setApplications() error {
 	applications, err := c.reader.ReadApplications()
 	if err != nil {
@@ -156,18 +332,28 @@ func (c *Cache) setApplications() error {
 
 	applicationsMap := make(map[string]*repository.Application)
 	applicationsMapByUserID := make(map[string][]*repository.Application)
+	applicationsMapByPublicKey := make(map[string]*repository.Application)
 
 	for i := 0; i < len(applications); i++ {
 		app := applications[i]
-		applicationID, userID := app.ID, app.UserID
+		applicationID, userID, publicKey := app.ID, app.UserID, app.GatewayAAT.ApplicationPublicKey
 
 		applicationsMap[applicationID] = app
 		applicationsMapByUserID[userID] = append(applicationsMapByUserID[userID], app)
+
+		if publicKey != "" {
+			applicationsMapByPublicKey[publicKey] = app
+		}
 	}
 
+	changed, removed := diffEntities(c.applicationsMap, applicationsMap)
+
 	c.applications = applications
 	c.applicationsMap = applicationsMap
 	c.applicationsMapByUserID = applicationsMapByUserID
+	c.applicationsMapByPublicKey = applicationsMapByPublicKey
+
+	c.trackChanges(CollectionApplications, changed, removed)
 
 	return nil
 }
@@ -204,6 +390,12 @@ func (c *Cache) addApplication(app repository.Application) {
 	c.applications = append(c.applications, &app)
 	c.applicationsMap[app.ID] = &app
 	c.applicationsMapByUserID[app.UserID] = append(c.applicationsMapByUserID[app.UserID], &app)
+
+	if app.GatewayAAT.ApplicationPublicKey != "" {
+		c.applicationsMapByPublicKey[app.GatewayAAT.ApplicationPublicKey] = &app
+	}
+
+	c.bumpVersion(CollectionApplications, app.ID)
 }
 
 func (c *Cache) addAppLimit(limit repository.AppLimit) {
@@ -224,6 +416,7 @@ func (c *Cache) addAppLimit(limit repository.AppLimit) {
 
 	if app, ok := c.applicationsMap[appID]; ok {
 		app.Limit = limit
+		c.bumpVersion(CollectionApplications, appID)
 		return
 	}
 
@@ -239,7 +432,19 @@ func (c *Cache) addGatewayAAT(aat repository.GatewayAAT) {
 
 	app := c.applicationsMap[appID]
 	if app != nil {
+		oldPublicKey := app.GatewayAAT.ApplicationPublicKey
+		if indexed := c.applicationsMapByPublicKey[oldPublicKey]; indexed != nil && indexed.ID == appID {
+			delete(c.applicationsMapByPublicKey, oldPublicKey)
+		}
+
 		app.GatewayAAT = aat
+
+		if aat.ApplicationPublicKey != "" {
+			c.applicationsMapByPublicKey[aat.ApplicationPublicKey] = app
+		}
+
+		c.bumpVersion(CollectionApplications, appID)
+
 		return
 	}
 
@@ -256,6 +461,7 @@ func (c *Cache) addGatewaySettings(settings repository.GatewaySettings) {
 	app := c.applicationsMap[appID]
 	if app != nil {
 		app.GatewaySettings = settings
+		c.bumpVersion(CollectionApplications, appID)
 		return
 	}
 
@@ -272,6 +478,7 @@ func (c *Cache) addNotificationSettings(settings repository.NotificationSettings
 	app := c.applicationsMap[appID]
 	if app != nil {
 		app.NotificationSettings = settings
+		c.bumpVersion(CollectionApplications, appID)
 		return
 	}
 
@@ -287,6 +494,9 @@ func (c *Cache) updateApplication(inApp repository.Application) {
 
 	if inApp.UserID == "" {
 		c.removeApplicationFromUserIDMap(inApp, app)
+	} else if !containsApplication(c.applicationsMapByUserID[inApp.UserID], app.ID) {
+		c.removeApplicationFromUserIDMap(inApp, app)
+		c.applicationsMapByUserID[inApp.UserID] = append(c.applicationsMapByUserID[inApp.UserID], app)
 	}
 
 	limit := app.Limit
@@ -299,10 +509,13 @@ func (c *Cache) updateApplication(inApp repository.Application) {
 	}
 
 	app.Name = inApp.Name
+	app.UserID = inApp.UserID
 	app.Status = inApp.Status
 	app.Limit = limit
 	app.FirstDateSurpassed = inApp.FirstDateSurpassed
 	app.UpdatedAt = inApp.UpdatedAt
+
+	c.bumpVersion(CollectionApplications, app.ID)
 }
 
 // removeApplicationFromUserIDMap removes applications saved in cache from the applicationsMapByUserID as they no longer have a userID
@@ -321,6 +534,64 @@ func (c *Cache) removeApplicationFromUserIDMap(inApp repository.Application, old
 	c.applicationsMapByUserID[userID] = appsForUserAfterRemove
 }
 
+// removeApplication removes a purged application from cache and from the load balancers holding it
+func (c *Cache) removeApplication(id string) {
+	c.rwMutex.Lock()
+	defer c.rwMutex.Unlock()
+
+	app := c.applicationsMap[id]
+	if app == nil {
+		return
+	}
+
+	delete(c.applicationsMap, id)
+	c.removeApplicationFromUserIDMap(*app, app)
+
+	if indexed := c.applicationsMapByPublicKey[app.GatewayAAT.ApplicationPublicKey]; indexed != nil && indexed.ID == id {
+		delete(c.applicationsMapByPublicKey, app.GatewayAAT.ApplicationPublicKey)
+	}
+
+	applications := make([]*repository.Application, 0, len(c.applications))
+	for _, cachedApp := range c.applications {
+		if cachedApp.ID != id {
+			applications = append(applications, cachedApp)
+		}
+	}
+
+	c.applications = applications
+
+	changedLBs := []string{}
+
+	for _, lb := range c.loadBalancers {
+		if !containsApplication(lb.Applications, id) {
+			continue
+		}
+
+		lbApps := make([]*repository.Application, 0, len(lb.Applications))
+		for _, lbApp := range lb.Applications {
+			if lbApp == nil || lbApp.ID != id {
+				lbApps = append(lbApps, lbApp)
+			}
+		}
+
+		lb.Applications = lbApps
+		changedLBs = append(changedLBs, lb.ID)
+	}
+
+	c.trackChanges(CollectionApplications, nil, []string{id})
+	c.trackChanges(CollectionLoadBalancers, changedLBs, nil)
+}
+
+func containsApplication(apps []*repository.Application, id string) bool {
+	for _, app := range apps {
+		if app != nil && app.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (c *Cache) setBlockchains() error {
 	blockchains, err := c.reader.ReadBlockchains()
 	if err != nil {
@@ -328,6 +599,7 @@ func (c *Cache) setBlockchains() error {
 	}
 
 	blockchainsMap := make(map[string]*repository.Blockchain)
+	blockchainsMapByAlias := make(map[string]*repository.Blockchain)
 
 	for _, blockchain := range blockchains {
 		if blockchainRedirects, exists := c.redirectsMapByBlockchainID[blockchain.ID]; exists {
@@ -339,10 +611,16 @@ func (c *Cache) setBlockchains() error {
 		}
 
 		blockchainsMap[blockchain.ID] = blockchain
+		c.indexBlockchainAliases(blockchainsMapByAlias, blockchain)
 	}
 
+	changed, removed := diffEntities(c.blockchainsMap, blockchainsMap)
+
 	c.blockchains = blockchains
 	c.blockchainsMap = blockchainsMap
+	c.blockchainsMapByAlias = blockchainsMapByAlias
+
+	c.trackChanges(CollectionBlockchains, changed, removed)
 
 	return nil
 }
@@ -360,6 +638,25 @@ func (c *Cache) addBlockchain(blockchain repository.Blockchain) {
 
 	c.blockchains = append(c.blockchains, &blockchain)
 	c.blockchainsMap[blockchain.ID] = &blockchain
+	c.indexBlockchainAliases(c.blockchainsMapByAlias, &blockchain)
+
+	c.bumpVersion(CollectionBlockchains, blockchain.ID)
+}
+
+// indexBlockchainAliases adds the blockchain aliases to the given index, aliases
+// already taken by another blockchain are reported and kept for the first one
+func (c *Cache) indexBlockchainAliases(aliasesMap map[string]*repository.Blockchain, blockchain *repository.Blockchain) {
+	for _, alias := range blockchain.BlockchainAliases {
+		alias = strings.ToLower(alias)
+
+		if indexed, ok := aliasesMap[alias]; ok && indexed.ID != blockchain.ID {
+			c.logError(fmt.Errorf("alias %s of blockchain %s already used by blockchain %s: %w",
+				alias, blockchain.ID, indexed.ID, errBlockchainAliasConflict))
+			continue
+		}
+
+		aliasesMap[alias] = blockchain
+	}
 }
 
 func (c *Cache) addSyncOptions(opts repository.SyncCheckOptions) {
@@ -369,6 +666,7 @@ func (c *Cache) addSyncOptions(opts repository.SyncCheckOptions) {
 	blockchain := c.blockchainsMap[opts.BlockchainID]
 	if blockchain != nil {
 		blockchain.SyncCheckOptions = opts
+		c.bumpVersion(CollectionBlockchains, blockchain.ID)
 		return
 	}
 
@@ -381,8 +679,34 @@ func (c *Cache) updateBlockchain(inBlockchain repository.Blockchain) {
 	defer c.rwMutex.Unlock()
 
 	blockchain := c.blockchainsMap[inBlockchain.ID]
+	if blockchain == nil {
+		return
+	}
+
+	blockchain.Altruist = inBlockchain.Altruist
+	blockchain.Blockchain = inBlockchain.Blockchain
+	blockchain.ChainID = inBlockchain.ChainID
+	blockchain.ChainIDCheck = inBlockchain.ChainIDCheck
+	blockchain.Description = inBlockchain.Description
+	blockchain.EnforceResult = inBlockchain.EnforceResult
+	blockchain.Network = inBlockchain.Network
+	blockchain.Path = inBlockchain.Path
+	blockchain.Ticker = inBlockchain.Ticker
+	blockchain.BlockchainAliases = inBlockchain.BlockchainAliases
+	blockchain.LogLimitBlocks = inBlockchain.LogLimitBlocks
+	blockchain.RequestTimeout = inBlockchain.RequestTimeout
 	blockchain.Active = inBlockchain.Active
 	blockchain.UpdatedAt = inBlockchain.UpdatedAt
+
+	for alias, indexed := range c.blockchainsMapByAlias {
+		if indexed.ID == blockchain.ID {
+			delete(c.blockchainsMapByAlias, alias)
+		}
+	}
+
+	c.indexBlockchainAliases(c.blockchainsMapByAlias, blockchain)
+
+	c.bumpVersion(CollectionBlockchains, blockchain.ID)
 }
 
 func (c *Cache) setLoadBalancers() error {
@@ -406,9 +730,33 @@ func (c *Cache) setLoadBalancers() error {
 		loadBalancersMapByUserID[loadBalancer.UserID] = append(loadBalancersMapByUserID[loadBalancer.UserID], loadBalancer)
 	}
 
+	removals, err := c.reader.ReadLoadBalancerRemovals()
+	if err != nil {
+		return fmt.Errorf("err in ReadLoadBalancerRemovals: %w", err)
+	}
+
+	loadBalancerRemovals := make(map[string]*types.LoadBalancerRemoval, len(removals))
+
+	for _, removal := range removals {
+		loadBalancerRemovals[removal.LoadBalancerID] = removal
+	}
+
+	changed, removed := diffEntities(c.loadBalancersMap, loadBalancersMap)
+	changedRemovals, removedRemovals := diffEntities(c.loadBalancerRemovals, loadBalancerRemovals)
+
 	c.loadBalancers = loadBalancers
 	c.loadBalancersMap = loadBalancersMap
 	c.loadBalancersMapByUserID = loadBalancersMapByUserID
+	c.loadBalancerRemovals = loadBalancerRemovals
+
+	// removing or restoring a load balancer changes it even when its row is the same
+	for _, id := range append(changedRemovals, removedRemovals...) {
+		if loadBalancersMap[id] != nil {
+			changed = append(changed, id)
+		}
+	}
+
+	c.trackChanges(CollectionLoadBalancers, changed, removed)
 
 	return nil
 }
@@ -435,6 +783,8 @@ func (c *Cache) addLoadBalancer(lb repository.LoadBalancer) {
 	c.loadBalancers = append(c.loadBalancers, &lb)
 	c.loadBalancersMap[lb.ID] = &lb
 	c.loadBalancersMapByUserID[lb.UserID] = append(c.loadBalancersMapByUserID[lb.UserID], &lb)
+
+	c.bumpVersion(CollectionLoadBalancers, lb.ID)
 }
 
 func (c *Cache) addStickinessOptions(opts repository.StickyOptions) {
@@ -447,6 +797,7 @@ func (c *Cache) addStickinessOptions(opts repository.StickyOptions) {
 	lb := c.loadBalancersMap[lbID]
 	if lb != nil {
 		lb.StickyOptions = opts
+		c.bumpVersion(CollectionLoadBalancers, lbID)
 		return
 	}
 
@@ -460,26 +811,38 @@ func (c *Cache) addLbApp(lbApp repository.LbApp) {
 	lb := c.loadBalancersMap[lbApp.LbID]
 	if lb != nil {
 		lb.Applications = append(lb.Applications, c.applicationsMap[lbApp.AppID])
+		c.bumpVersion(CollectionLoadBalancers, lb.ID)
 		return
 	}
 
 	c.pendingLbApps[lbApp.LbID] = append(c.pendingLbApps[lbApp.LbID], lbApp)
 }
 
-// updateLoadBalancer updates load balancer saved in cache
-func (c *Cache) updateLoadBalancer(inLB repository.LoadBalancer) {
+// updateLoadBalancer updates load balancer saved in cache along with its removal, nil when it is not removed
+func (c *Cache) updateLoadBalancer(inLB repository.LoadBalancer, removal *types.LoadBalancerRemoval) {
 	c.rwMutex.Lock()
 	defer c.rwMutex.Unlock()
 
 	lb := c.loadBalancersMap[inLB.ID]
 
+	if removal != nil {
+		c.loadBalancerRemovals[inLB.ID] = removal
+	} else {
+		delete(c.loadBalancerRemovals, inLB.ID)
+	}
+
 	if inLB.UserID == "" {
 		c.removeLoadBalancerFromUserIDMap(inLB, lb)
+	} else if !containsLoadBalancer(c.loadBalancersMapByUserID[inLB.UserID], lb.ID) {
+		c.removeLoadBalancerFromUserIDMap(inLB, lb)
+		c.loadBalancersMapByUserID[inLB.UserID] = append(c.loadBalancersMapByUserID[inLB.UserID], lb)
 	}
 
 	lb.Name = inLB.Name
 	lb.UserID = inLB.UserID
 	lb.UpdatedAt = inLB.UpdatedAt
+
+	c.bumpVersion(CollectionLoadBalancers, lb.ID)
 }
 
 // removeApplication removes load balancers saved in cache from the loadBalancersMapByUserID as they no longer have a userID
@@ -498,6 +861,42 @@ func (c *Cache) removeLoadBalancerFromUserIDMap(inLB repository.LoadBalancer, ol
 	c.loadBalancersMapByUserID[userID] = lbsForUserAfterRemove
 }
 
+// removeLoadBalancer removes a purged load balancer from cache
+func (c *Cache) removeLoadBalancer(id string) {
+	c.rwMutex.Lock()
+	defer c.rwMutex.Unlock()
+
+	lb := c.loadBalancersMap[id]
+	if lb == nil {
+		return
+	}
+
+	delete(c.loadBalancersMap, id)
+	delete(c.loadBalancerRemovals, id)
+	c.removeLoadBalancerFromUserIDMap(*lb, lb)
+
+	loadBalancers := make([]*repository.LoadBalancer, 0, len(c.loadBalancers))
+	for _, cachedLB := range c.loadBalancers {
+		if cachedLB.ID != id {
+			loadBalancers = append(loadBalancers, cachedLB)
+		}
+	}
+
+	c.loadBalancers = loadBalancers
+
+	c.trackChanges(CollectionLoadBalancers, nil, []string{id})
+}
+
+func containsLoadBalancer(lbs []*repository.LoadBalancer, id string) bool {
+	for _, lb := range lbs {
+		if lb.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (c *Cache) setPayPlans() error {
 	payPlans, err := c.reader.ReadPayPlans()
 	if err != nil {
@@ -510,9 +909,13 @@ func (c *Cache) setPayPlans() error {
 		payPlansMap[payPlan.Type] = payPlan
 	}
 
+	changed, removed := diffEntities(c.payPlansMap, payPlansMap)
+
 	c.payPlans = payPlans
 	c.payPlansMap = payPlansMap
 
+	c.trackChanges(CollectionPayPlans, changed, removed)
+
 	return nil
 }
 
@@ -523,12 +926,19 @@ func (c *Cache) setRedirects() error {
 	}
 
 	redirectsMap := make(map[string][]*repository.Redirect)
+	redirectsMapByDomain := make(map[string]*repository.Redirect)
 
 	for _, redirect := range redirects {
 		redirectsMap[redirect.BlockchainID] = append(redirectsMap[redirect.BlockchainID], redirect)
+		redirectsMapByDomain[normalizeDomain(redirect.Domain)] = redirect
 	}
 
+	changed, removed := diffEntities(c.redirectsMapByDomain, redirectsMapByDomain)
+
 	c.redirectsMapByBlockchainID = redirectsMap
+	c.redirectsMapByDomain = redirectsMapByDomain
+
+	c.trackChanges(CollectionRedirects, changed, removed)
 
 	return nil
 }
@@ -536,11 +946,17 @@ func (c *Cache) setRedirects() error {
 // AddRedirects adds blockchain redirect to cache and updates cached blockchain entry
 func (c *Cache) addRedirect(redirect repository.Redirect) {
 	c.rwMutex.Lock()
+	defer c.rwMutex.Unlock()
+
+	domain := normalizeDomain(redirect.Domain)
+
 	c.redirectsMapByBlockchainID[redirect.BlockchainID] = append(c.redirectsMapByBlockchainID[redirect.BlockchainID], &redirect)
-	c.rwMutex.Unlock()
+	c.redirectsMapByDomain[domain] = &redirect
+	c.bumpVersion(CollectionRedirects, domain)
 
-	blockchain := c.GetBlockchain(redirect.BlockchainID)
+	blockchain := c.blockchainsMap[redirect.BlockchainID]
 	blockchain.Redirects = append(blockchain.Redirects, redirect)
+	c.bumpVersion(CollectionBlockchains, blockchain.ID)
 }
 
 // SetCache gets all values from DB and stores them in cache
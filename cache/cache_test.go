@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/pokt-foundation/pocket-http-db/types"
 	"github.com/pokt-foundation/portal-api-go/repository"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
@@ -51,6 +52,8 @@ func TestCache_SetCache(t *testing.T) {
 		{ID: "0021"},
 	}, nil)
 
+	readerMock.On("ReadLoadBalancerRemovals").Return([]*types.LoadBalancerRemoval{}, nil)
+
 	readerMock.On("ReadLoadBalancers").Return([]*repository.LoadBalancer{
 		{
 			ID:     "60ecb2bf67774900350d9c42",
@@ -103,6 +106,12 @@ func TestCache_SetCache(t *testing.T) {
 	c.Len(cache.GetLoadBalancers(), 1)
 	c.Len(cache.GetLoadBalancersByUserID("60ecb35fts687463gh2h72gs"), 1)
 
+	c.Equal([]string{
+		"60ecb2bf67774900350d9c43",
+		"60ecb2bf67774900350d9c44",
+		"60ecb35fts687463gh2h72gs",
+	}, cache.GetUserIDs())
+
 	c.NotEmpty(cache.GetPayPlan(repository.FreetierV0))
 	c.Len(cache.GetPayPlans(), 2)
 
@@ -180,6 +189,8 @@ func TestCache_SetCacheFailure(t *testing.T) {
 	}, nil)
 
 	errOnLoadBalancer := errors.New("error on loadbalancers")
+	readerMock.On("ReadLoadBalancerRemovals").Return([]*types.LoadBalancerRemoval{}, nil)
+
 	readerMock.On("ReadLoadBalancers").Return([]*repository.LoadBalancer{}, errOnLoadBalancer).Once()
 
 	err = cache.SetCache()
@@ -245,6 +256,59 @@ func TestCache_AddApplication(t *testing.T) {
 	c.Len(cache.GetApplications(), 4)
 	c.Len(cache.GetApplicationsByUserID("60ecb2bf67774900350d9c43"), 3)
 	c.Equal(cache.GetApplication("5f62b7d8be3591c4dea8566b").DailyLimit(), 250000)
+
+	apps, missing := cache.GetApplicationsByIDs([]string{"5f62b7d8be3591c4dea8566b", "5f62b7d8be3591c4dea8566c"})
+	c.Len(apps, 1)
+	c.Equal("5f62b7d8be3591c4dea8566b", apps[0].ID)
+	c.Equal([]string{"5f62b7d8be3591c4dea8566c"}, missing)
+}
+
+func TestCache_GetApplicationByPublicKey(t *testing.T) {
+	c := require.New(t)
+
+	readerMock := &ReaderMock{}
+
+	readerMock.On("ReadApplications").Return([]*repository.Application{
+		{
+			ID:         "5f62b7d8be3591c4dea8566d",
+			UserID:     "60ecb2bf67774900350d9c43",
+			GatewayAAT: repository.GatewayAAT{ApplicationPublicKey: "pub_566d"},
+		},
+		{
+			ID:     "5f62b7d8be3591c4dea8566a",
+			UserID: "60ecb2bf67774900350d9c43",
+		},
+	}, nil)
+
+	cache := NewCache(readerMock, logrus.New())
+
+	err := cache.setApplications()
+	c.NoError(err)
+
+	c.Equal("5f62b7d8be3591c4dea8566d", cache.GetApplicationByPublicKey("pub_566d").ID)
+	c.Nil(cache.GetApplicationByPublicKey(""))
+
+	cache.addGatewayAAT(repository.GatewayAAT{ID: "5f62b7d8be3591c4dea8566a", ApplicationPublicKey: "pub_566a"})
+	c.Equal("5f62b7d8be3591c4dea8566a", cache.GetApplicationByPublicKey("pub_566a").ID)
+
+	cache.addGatewayAAT(repository.GatewayAAT{ID: "5f62b7d8be3591c4dea8566d", ApplicationPublicKey: "pub_566d_new"})
+	c.Nil(cache.GetApplicationByPublicKey("pub_566d"))
+	c.Equal("5f62b7d8be3591c4dea8566d", cache.GetApplicationByPublicKey("pub_566d_new").ID)
+
+	cache.addGatewayAAT(repository.GatewayAAT{ID: "5f62b7d8be3591c4dea8566b", ApplicationPublicKey: "pub_566b"})
+	c.Nil(cache.GetApplicationByPublicKey("pub_566b"))
+
+	cache.addApplication(repository.Application{
+		ID:     "5f62b7d8be3591c4dea8566b",
+		UserID: "60ecb2bf67774900350d9c43",
+	})
+	c.Equal("5f62b7d8be3591c4dea8566b", cache.GetApplicationByPublicKey("pub_566b").ID)
+
+	apps, missing := cache.GetApplicationsByPublicKeys([]string{"pub_566a", "pub_566x", "pub_566b"})
+	c.Len(apps, 2)
+	c.Equal("5f62b7d8be3591c4dea8566a", apps[0].ID)
+	c.Equal("5f62b7d8be3591c4dea8566b", apps[1].ID)
+	c.Equal([]string{"pub_566x"}, missing)
 }
 
 func TestCache_UpdateApplication(t *testing.T) {
@@ -278,6 +342,8 @@ func TestCache_UpdateApplication(t *testing.T) {
 		},
 	}, nil)
 
+	readerMock.On("ReadLoadBalancerRemovals").Return([]*types.LoadBalancerRemoval{}, nil)
+
 	readerMock.On("ReadLoadBalancers").Return([]*repository.LoadBalancer{
 		{
 			ID:     "60ecb2bf67774900350d9c42",
@@ -340,6 +406,8 @@ func TestCache_RemoveApplication(t *testing.T) {
 		},
 	}, nil)
 
+	readerMock.On("ReadLoadBalancerRemovals").Return([]*types.LoadBalancerRemoval{}, nil)
+
 	readerMock.On("ReadLoadBalancers").Return([]*repository.LoadBalancer{
 		{
 			ID:     "60ecb2bf67774900350d9c42",
@@ -373,6 +441,8 @@ func TestCache_AddLoadBalancer(t *testing.T) {
 
 	readerMock := &ReaderMock{}
 
+	readerMock.On("ReadLoadBalancerRemovals").Return([]*types.LoadBalancerRemoval{}, nil)
+
 	readerMock.On("ReadLoadBalancers").Return([]*repository.LoadBalancer{
 		{
 			ID:     "5f62b7d8be3591c4dea8566d",
@@ -410,6 +480,11 @@ func TestCache_AddLoadBalancer(t *testing.T) {
 
 	c.Len(cache.GetLoadBalancers(), 4)
 	c.Len(cache.GetLoadBalancersByUserID("60ecb2bf67774900350d9c43"), 3)
+
+	lbs, missing := cache.GetLoadBalancersByIDs([]string{"5f62b7d8be3591c4dea8566b", "5f62b7d8be3591c4dea8566c"})
+	c.Len(lbs, 1)
+	c.Equal("5f62b7d8be3591c4dea8566b", lbs[0].ID)
+	c.Equal([]string{"5f62b7d8be3591c4dea8566c"}, missing)
 }
 
 func TestCache_UpdateLoadBalancer(t *testing.T) {
@@ -417,6 +492,8 @@ func TestCache_UpdateLoadBalancer(t *testing.T) {
 
 	readerMock := &ReaderMock{}
 
+	readerMock.On("ReadLoadBalancerRemovals").Return([]*types.LoadBalancerRemoval{}, nil)
+
 	readerMock.On("ReadLoadBalancers").Return([]*repository.LoadBalancer{
 		{
 			ID:     "5f62b7d8be3591c4dea8566d",
@@ -441,7 +518,7 @@ func TestCache_UpdateLoadBalancer(t *testing.T) {
 		ID:     "5f62b7d8be3591c4dea8566a",
 		UserID: "60ecb2bf67774900350d9c43",
 		Name:   "papolo",
-	})
+	}, nil)
 
 	c.Len(cache.GetLoadBalancers(), 3)
 	c.Len(cache.GetLoadBalancersByUserID("60ecb2bf67774900350d9c43"), 2)
@@ -455,6 +532,8 @@ func TestCache_RemoveLoadBalancer(t *testing.T) {
 
 	readerMock := &ReaderMock{}
 
+	readerMock.On("ReadLoadBalancerRemovals").Return([]*types.LoadBalancerRemoval{}, nil)
+
 	readerMock.On("ReadLoadBalancers").Return([]*repository.LoadBalancer{
 		{
 			ID:     "5f62b7d8be3591c4dea8566d",
@@ -475,7 +554,7 @@ func TestCache_RemoveLoadBalancer(t *testing.T) {
 	cache.updateLoadBalancer(repository.LoadBalancer{
 		ID:     "5f62b7d8be3591c4dea8566a",
 		UserID: "",
-	})
+	}, nil)
 
 	c.Len(cache.GetLoadBalancersByUserID("60ecb2bf67774900350d9c43"), 1)
 }
@@ -501,13 +580,39 @@ func TestCache_AddBlockchain(t *testing.T) {
 	c.Len(cache.GetBlockchains(), 2)
 }
 
+func TestCache_GetBlockchainByAlias(t *testing.T) {
+	c := require.New(t)
+
+	readerMock := &ReaderMock{}
+
+	readerMock.On("ReadBlockchains").Return([]*repository.Blockchain{
+		{ID: "0001", BlockchainAliases: []string{"pokt-mainnet", "POKT"}},
+		{ID: "0002", BlockchainAliases: []string{"eth-mainnet", "pokt"}},
+	}, nil)
+
+	cache := NewCache(readerMock, logrus.New())
+
+	err := cache.setBlockchains()
+	c.NoError(err)
+
+	c.Equal("0001", cache.GetBlockchainByAlias("pokt-mainnet").ID)
+	c.Equal("0001", cache.GetBlockchainByAlias("pokt").ID)
+	c.Equal("0002", cache.GetBlockchainByAlias("ETH-mainnet").ID)
+	c.Nil(cache.GetBlockchainByAlias("poly-mainnet"))
+
+	cache.addBlockchain(repository.Blockchain{ID: "0003", BlockchainAliases: []string{"poly-mainnet", "eth-mainnet"}})
+
+	c.Equal("0003", cache.GetBlockchainByAlias("poly-mainnet").ID)
+	c.Equal("0002", cache.GetBlockchainByAlias("eth-mainnet").ID)
+}
+
 func TestCache_UpdateBlockchain(t *testing.T) {
 	c := require.New(t)
 
 	readerMock := &ReaderMock{}
 
 	readerMock.On("ReadBlockchains").Return([]*repository.Blockchain{
-		{ID: "0001", Active: false},
+		{ID: "0001", Active: false, BlockchainAliases: []string{"pokt-mainnet"}},
 	}, nil)
 
 	cache := NewCache(readerMock, logrus.New())
@@ -519,12 +624,19 @@ func TestCache_UpdateBlockchain(t *testing.T) {
 	c.Equal(cache.GetBlockchains()[0].Active, false)
 
 	cache.updateBlockchain(repository.Blockchain{
-		ID:     "0001",
-		Active: true,
+		ID:                "0001",
+		Active:            true,
+		Altruist:          "https://altruist.com",
+		LogLimitBlocks:    1000,
+		BlockchainAliases: []string{"pokt"},
 	})
 
 	c.Len(cache.GetBlockchains(), 1)
 	c.Equal(cache.GetBlockchains()[0].Active, true)
+	c.Equal("https://altruist.com", cache.GetBlockchains()[0].Altruist)
+	c.Equal(1000, cache.GetBlockchains()[0].LogLimitBlocks)
+	c.Nil(cache.GetBlockchainByAlias("pokt-mainnet"))
+	c.Equal("0001", cache.GetBlockchainByAlias("pokt").ID)
 }
 
 func TestCache_AddRedirect(t *testing.T) {
@@ -559,3 +671,33 @@ func TestCache_AddRedirect(t *testing.T) {
 	c.Len(cache.GetBlockchains()[0].Redirects, 3)
 	c.Len(cache.GetRedirects("0001"), 3)
 }
+
+func TestCache_GetRedirectByDomain(t *testing.T) {
+	c := require.New(t)
+
+	readerMock := &ReaderMock{}
+
+	readerMock.On("ReadRedirects").Return([]*repository.Redirect{
+		{BlockchainID: "0001", Alias: "pokt-mainnet", Domain: "pokt-mainnet.gateway.network"},
+		{BlockchainID: "0002", Alias: "eth-mainnet", Domain: "*.eth.gateway.network"},
+	}, nil)
+
+	cache := NewCache(readerMock, logrus.New())
+
+	err := cache.setRedirects()
+	c.NoError(err)
+
+	c.Equal("0001", cache.GetRedirectByDomain("pokt-mainnet.gateway.network").BlockchainID)
+	c.Equal("0001", cache.GetRedirectByDomain("POKT-mainnet.gateway.network:443").BlockchainID)
+	c.Equal("0002", cache.GetRedirectByDomain("archival.eth.gateway.network").BlockchainID)
+	c.Equal("0002", cache.GetRedirectByDomain("a.b.eth.gateway.network").BlockchainID)
+	c.Nil(cache.GetRedirectByDomain("eth.gateway.network"))
+	c.Nil(cache.GetRedirectByDomain("gateway.network"))
+
+	cache.redirectsMapByBlockchainID = map[string][]*repository.Redirect{}
+	cache.blockchainsMap = map[string]*repository.Blockchain{"0003": {ID: "0003"}}
+
+	cache.addRedirect(repository.Redirect{BlockchainID: "0003", Alias: "poly-mainnet", Domain: "poly-mainnet.gateway.network"})
+
+	c.Equal("0003", cache.GetRedirectByDomain("poly-mainnet.gateway.network").BlockchainID)
+}
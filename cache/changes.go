@@ -0,0 +1,134 @@
+package cache
+
+import "github.com/pokt-foundation/portal-api-go/repository"
+
+// changeLogSize is the number of removals kept to answer change queries, queries
+// older than the oldest removal kept are answered with a full resync
+const changeLogSize = 1024
+
+// changesCollections are the collections reported by change queries
+var changesCollections = map[Collection]bool{
+	CollectionApplications:  true,
+	CollectionBlockchains:   true,
+	CollectionLoadBalancers: true,
+}
+
+// Removal represents an entity removed from cache at a given generation
+type Removal struct {
+	Collection Collection
+	ID         string
+	Generation uint64
+}
+
+// Changes holds the cached entities that changed after a given generation
+type Changes struct {
+	Generation    uint64
+	Resync        bool
+	Applications  []*repository.Application
+	LoadBalancers []*repository.LoadBalancer
+	Blockchains   []*repository.Blockchain
+	Removed       []Removal
+}
+
+// changeLog is a ring buffer of the latest removals
+type changeLog struct {
+	entries []Removal
+	next    int
+	full    bool
+	dropped uint64 // highest generation evicted from the buffer
+}
+
+func (l *changeLog) add(removal Removal) {
+	if l.entries == nil {
+		l.entries = make([]Removal, changeLogSize)
+	}
+
+	if l.full {
+		l.dropped = l.entries[l.next].Generation
+	}
+
+	l.entries[l.next] = removal
+	l.next = (l.next + 1) % len(l.entries)
+
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// since returns the removals after the given generation from oldest to newest
+func (l *changeLog) since(generation uint64) []Removal {
+	removals := []Removal{}
+
+	start, count := 0, l.next
+	if l.full {
+		start, count = l.next, len(l.entries)
+	}
+
+	for i := 0; i < count; i++ {
+		removal := l.entries[(start+i)%len(l.entries)]
+		if removal.Generation > generation {
+			removals = append(removals, removal)
+		}
+	}
+
+	return removals
+}
+
+// GetChangesSince returns the applications, load balancers and blockchains whose version is
+// newer than the given generation along with the entities removed since then, when the
+// generation is unknown to this cache or older than the change log all entities are
+// returned and the result is flagged for resync
+func (c *Cache) GetChangesSince(generation uint64) Changes {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	changes := Changes{
+		Generation:    c.generation,
+		Applications:  []*repository.Application{},
+		LoadBalancers: []*repository.LoadBalancer{},
+		Blockchains:   []*repository.Blockchain{},
+		Removed:       []Removal{},
+	}
+
+	if generation > c.generation || generation < c.removals.dropped {
+		changes.Resync = true
+		generation = 0
+	}
+
+	for _, app := range c.applications {
+		if c.entityVersion(CollectionApplications, app.ID).Generation > generation {
+			changes.Applications = append(changes.Applications, app)
+		}
+	}
+
+	for _, lb := range c.loadBalancers {
+		if c.entityVersion(CollectionLoadBalancers, lb.ID).Generation > generation {
+			changes.LoadBalancers = append(changes.LoadBalancers, lb)
+		}
+	}
+
+	for _, blockchain := range c.blockchains {
+		if c.entityVersion(CollectionBlockchains, blockchain.ID).Generation > generation {
+			changes.Blockchains = append(changes.Blockchains, blockchain)
+		}
+	}
+
+	if changes.Resync {
+		return changes
+	}
+
+	for _, removal := range c.removals.since(generation) {
+		if !changesCollections[removal.Collection] {
+			continue
+		}
+
+		// Entities added back after their removal are already reported as changed
+		if _, ok := c.entityVersions[removal.Collection][removal.ID]; ok {
+			continue
+		}
+
+		changes.Removed = append(changes.Removed, removal)
+	}
+
+	return changes
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+	"github.com/pokt-foundation/portal-api-go/repository"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_GetChangesSince(t *testing.T) {
+	c := require.New(t)
+
+	readerMock := &ReaderMock{}
+
+	readerMock.On("ReadApplications").Return([]*repository.Application{
+		{ID: "5f62b7d8be3591c4dea8566d"},
+		{ID: "5f62b7d8be3591c4dea8566a"},
+	}, nil).Once()
+
+	readerMock.On("ReadBlockchains").Return([]*repository.Blockchain{
+		{ID: "0021"},
+	}, nil)
+
+	readerMock.On("ReadLoadBalancerRemovals").Return([]*types.LoadBalancerRemoval{}, nil)
+
+	readerMock.On("ReadLoadBalancers").Return([]*repository.LoadBalancer{
+		{ID: "60ecb2bf67774900350d9c42", ApplicationIDs: []string{"5f62b7d8be3591c4dea8566d"}},
+	}, nil)
+
+	cache := NewCache(readerMock, logrus.New())
+
+	c.NoError(cache.setBlockchains())
+	c.NoError(cache.setApplications())
+	c.NoError(cache.setLoadBalancers())
+
+	changes := cache.GetChangesSince(0)
+	c.False(changes.Resync)
+	c.Len(changes.Applications, 2)
+	c.Len(changes.LoadBalancers, 1)
+	c.Len(changes.Blockchains, 1)
+	c.Empty(changes.Removed)
+
+	cursor := changes.Generation
+
+	changes = cache.GetChangesSince(cursor)
+	c.Equal(cursor, changes.Generation)
+	c.Empty(changes.Applications)
+	c.Empty(changes.LoadBalancers)
+	c.Empty(changes.Blockchains)
+
+	// An application update also reports the load balancers holding it
+	cache.updateApplication(repository.Application{ID: "5f62b7d8be3591c4dea8566d", UserID: "60ecb2bf67774900350d9c43", Name: "orlando"})
+
+	changes = cache.GetChangesSince(cursor)
+	c.Len(changes.Applications, 1)
+	c.Equal("orlando", changes.Applications[0].Name)
+	c.Len(changes.LoadBalancers, 1)
+	c.Empty(changes.Blockchains)
+
+	cursor = changes.Generation
+
+	readerMock.On("ReadApplications").Return([]*repository.Application{
+		{ID: "5f62b7d8be3591c4dea8566d", UserID: "60ecb2bf67774900350d9c43", Name: "orlando"},
+	}, nil).Once()
+
+	c.NoError(cache.setApplications())
+
+	changes = cache.GetChangesSince(cursor)
+	c.Empty(changes.Applications)
+	c.Equal([]Removal{{
+		Collection: CollectionApplications,
+		ID:         "5f62b7d8be3591c4dea8566a",
+		Generation: changes.Generation,
+	}}, changes.Removed)
+
+	// Entities added back are reported as changes instead of removals
+	cache.addApplication(repository.Application{ID: "5f62b7d8be3591c4dea8566a"})
+
+	changes = cache.GetChangesSince(cursor)
+	c.Len(changes.Applications, 1)
+	c.Empty(changes.Removed)
+
+	// Unknown generations ask for a resync
+	changes = cache.GetChangesSince(changes.Generation + 1)
+	c.True(changes.Resync)
+	c.Len(changes.Applications, 2)
+	c.Empty(changes.Removed)
+}
+
+func TestChangeLog(t *testing.T) {
+	c := require.New(t)
+
+	var log changeLog
+
+	for i := 1; i <= changeLogSize; i++ {
+		log.add(Removal{ID: "id", Generation: uint64(i)})
+	}
+
+	c.Zero(log.dropped)
+	c.Len(log.since(0), changeLogSize)
+	c.Len(log.since(changeLogSize-2), 2)
+
+	log.add(Removal{ID: "id", Generation: changeLogSize + 1})
+	log.add(Removal{ID: "id", Generation: changeLogSize + 2})
+
+	c.Equal(uint64(2), log.dropped)
+
+	removals := log.since(changeLogSize)
+	c.Len(removals, 2)
+	c.Equal(uint64(changeLogSize+1), removals[0].Generation)
+	c.Equal(uint64(changeLogSize+2), removals[1].Generation)
+	c.Equal(uint64(3), log.since(0)[0].Generation)
+}
@@ -8,6 +8,10 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// actionDelete is the action of the notifications sent for deleted rows, not defined upstream
+// as only the applications and loadbalancers tables notify deletes when expired entities are purged
+const actionDelete repository.Action = "DELETE"
+
 var (
 	errParseApplicationFailed          = errors.New("parse application failed")
 	errParseBlockchainFailed           = errors.New("parse blockchain failed")
@@ -42,6 +46,9 @@ func (c *Cache) parseApplicationNotification(n repository.Notification) {
 	if n.Action == repository.ActionUpdate {
 		c.updateApplication(*app)
 	}
+	if n.Action == actionDelete {
+		c.removeApplication(app.ID)
+	}
 }
 
 func (c *Cache) parseBlockchainNotification(n repository.Notification) {
@@ -78,7 +85,7 @@ func (c *Cache) parseGatewayAATNotification(n repository.Notification) {
 		return
 	}
 
-	if n.Action == repository.ActionInsert {
+	if n.Action == repository.ActionInsert || n.Action == repository.ActionUpdate {
 		c.addGatewayAAT(*aat)
 	}
 }
@@ -106,7 +113,18 @@ func (c *Cache) parseLoadBalancerNotification(n repository.Notification) {
 		c.addLoadBalancer(*lb)
 	}
 	if n.Action == repository.ActionUpdate {
-		c.updateLoadBalancer(*lb)
+		// removals are only read by PHD so they are not notified, removing and restoring update the row though,
+		// when the removal cannot be read the update is still applied keeping the removal cached until the next refresh
+		removal, err := c.reader.ReadLoadBalancerRemoval(lb.ID)
+		if err != nil {
+			c.logError(fmt.Errorf("parseLoadBalancerNotification read removal failed: %w", err))
+			removal = c.GetLoadBalancerRemoval(lb.ID)
+		}
+
+		c.updateLoadBalancer(*lb, removal)
+	}
+	if n.Action == actionDelete {
+		c.removeLoadBalancer(lb.ID)
 	}
 }
 
@@ -1,9 +1,11 @@
 package cache
 
 import (
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/pokt-foundation/pocket-http-db/types"
 	"github.com/pokt-foundation/portal-api-go/repository"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
@@ -47,6 +49,8 @@ func newMockCache(readerMock *ReaderMock) *Cache {
 		{ID: "0021"},
 	}, nil)
 
+	readerMock.On("ReadLoadBalancerRemovals").Return([]*types.LoadBalancerRemoval{}, nil)
+
 	readerMock.On("ReadLoadBalancers").Return([]*repository.LoadBalancer{
 		{
 			ID:     "60ecb2bf67774900350d9c42",
@@ -173,6 +177,87 @@ func TestCache_listenApplication(t *testing.T) {
 	c.Empty(apps)
 }
 
+func TestCache_listenApplicationRestoreAndPurge(t *testing.T) {
+	c := require.New(t)
+
+	readerMock := NewReaderMock()
+	cache := newMockCache(readerMock)
+
+	generation := cache.GetCollectionVersion(CollectionApplications).Generation
+
+	// Removed application loses its owner and gets it back on restore
+	readerMock.lMock.MockEvent(repository.ActionUpdate, repository.ActionUpdate, &repository.Application{
+		ID:     "5f62b7d8be3591c4dea8566a",
+		Status: repository.AwaitingGracePeriod,
+	})
+
+	time.Sleep(1 * time.Second) // need time for cache refresh
+
+	c.Len(cache.GetApplicationsByUserID("60ecb2bf67774900350d9c43"), 1)
+
+	readerMock.lMock.MockEvent(repository.ActionUpdate, repository.ActionUpdate, &repository.Application{
+		ID:     "5f62b7d8be3591c4dea8566a",
+		UserID: "60ecb2bf67774900350d9c43",
+		Status: repository.InService,
+	})
+
+	time.Sleep(1 * time.Second) // need time for cache refresh
+
+	c.Len(cache.GetApplicationsByUserID("60ecb2bf67774900350d9c43"), 2)
+	c.Equal(repository.InService, cache.GetApplication("5f62b7d8be3591c4dea8566a").Status)
+
+	// Purged application is removed everywhere
+	readerMock.lMock.MockEvent(actionDelete, actionDelete, &repository.Application{
+		ID: "5f62b7d8be3591c4dea8566a",
+	})
+
+	time.Sleep(1 * time.Second) // need time for cache refresh
+
+	c.Nil(cache.GetApplication("5f62b7d8be3591c4dea8566a"))
+	c.Len(cache.GetApplications(), 2)
+	c.Len(cache.GetApplicationsByUserID("60ecb2bf67774900350d9c43"), 1)
+
+	lb := cache.GetLoadBalancer("60ecb2bf67774900350d9c42")
+	c.Len(lb.Applications, 1)
+	c.Equal("5f62b7d8be3591c4dea8566d", lb.Applications[0].ID)
+
+	changes := cache.GetChangesSince(generation)
+	c.Len(changes.Removed, 1)
+	c.Equal(CollectionApplications, changes.Removed[0].Collection)
+	c.Equal("5f62b7d8be3591c4dea8566a", changes.Removed[0].ID)
+	c.Len(changes.LoadBalancers, 1)
+}
+
+func TestCache_listenOwnershipTransfer(t *testing.T) {
+	c := require.New(t)
+
+	readerMock := NewReaderMock()
+	cache := newMockCache(readerMock)
+
+	c.Len(cache.GetApplicationsByUserID("60ecb2bf67774900350d9c43"), 2)
+
+	readerMock.On("ReadLoadBalancerRemoval", "60ecb2bf67774900350d9c42").Return((*types.LoadBalancerRemoval)(nil), nil)
+
+	readerMock.lMock.MockEvent(repository.ActionUpdate, repository.ActionUpdate, &repository.Application{
+		ID:     "5f62b7d8be3591c4dea8566d",
+		UserID: "60ecb2bf67774900350d9c44",
+	})
+	readerMock.lMock.MockEvent(repository.ActionUpdate, repository.ActionUpdate, &repository.LoadBalancer{
+		ID:     "60ecb2bf67774900350d9c42",
+		UserID: "60ecb2bf67774900350d9c44",
+	})
+
+	time.Sleep(1 * time.Second) // need time for cache refresh
+
+	c.Len(cache.GetApplicationsByUserID("60ecb2bf67774900350d9c43"), 1)
+	c.Len(cache.GetApplicationsByUserID("60ecb2bf67774900350d9c44"), 2)
+	c.Equal("60ecb2bf67774900350d9c44", cache.GetApplication("5f62b7d8be3591c4dea8566d").UserID)
+
+	c.Empty(cache.GetLoadBalancersByUserID("60ecb35fts687463gh2h72gs"))
+	c.Len(cache.GetLoadBalancersByUserID("60ecb2bf67774900350d9c44"), 1)
+	c.Equal("60ecb2bf67774900350d9c44", cache.GetLoadBalancer("60ecb2bf67774900350d9c42").UserID)
+}
+
 func TestCache_listenAppLimit(t *testing.T) {
 	c := require.New(t)
 
@@ -217,6 +302,41 @@ func TestCache_listenAppLimit(t *testing.T) {
 	c.Len(pendingUpdates, 0)
 }
 
+func TestCache_listenGatewayAAT(t *testing.T) {
+	c := require.New(t)
+
+	readerMock := NewReaderMock()
+	cache := newMockCache(readerMock)
+
+	readerMock.lMock.MockEvent(repository.ActionInsert, repository.ActionInsert, &repository.Application{
+		ID: "321",
+		GatewayAAT: repository.GatewayAAT{
+			ApplicationPublicKey: "old_public_key",
+			PrivateKey:           "old_private_key",
+		},
+	})
+
+	time.Sleep(1 * time.Second) // need time for cache refresh
+
+	c.NotNil(cache.GetApplicationByPublicKey("old_public_key"))
+
+	readerMock.lMock.MockEvent(repository.ActionUpdate, repository.ActionUpdate, &repository.Application{
+		ID: "321",
+		GatewayAAT: repository.GatewayAAT{
+			ApplicationPublicKey: "new_public_key",
+			PrivateKey:           "new_private_key",
+		},
+	})
+
+	time.Sleep(1 * time.Second) // need time for cache refresh
+
+	app := cache.GetApplication("321")
+	c.Equal("new_private_key", app.GatewayAAT.PrivateKey)
+	c.Empty(app.GatewayAAT.ID)
+	c.Nil(cache.GetApplicationByPublicKey("old_public_key"))
+	c.Equal("321", cache.GetApplicationByPublicKey("new_public_key").ID)
+}
+
 func TestCache_listenBlockchain(t *testing.T) {
 	c := require.New(t)
 
@@ -239,14 +359,25 @@ func TestCache_listenBlockchain(t *testing.T) {
 	c.Equal("yeh", blockchain.SyncCheckOptions.Body)
 
 	readerMock.lMock.MockEvent(repository.ActionUpdate, repository.ActionUpdate, &repository.Blockchain{
-		ID:     "0023",
-		Active: true,
+		ID:                "0023",
+		Active:            true,
+		Altruist:          "https://altruist.com",
+		RequestTimeout:    5000,
+		BlockchainAliases: []string{"test-mainnet"},
+		SyncCheckOptions: repository.SyncCheckOptions{
+			BlockchainID: "0023",
+			Body:         "yah",
+		},
 	})
 
 	time.Sleep(1 * time.Second) // need time for cache refresh
 
 	blockchain = cache.GetBlockchain("0023")
 	c.True(blockchain.Active)
+	c.Equal("https://altruist.com", blockchain.Altruist)
+	c.Equal(5000, blockchain.RequestTimeout)
+	c.Equal("yah", blockchain.SyncCheckOptions.Body)
+	c.Equal("0023", cache.GetBlockchainByAlias("test-mainnet").ID)
 }
 
 func TestCache_listenLoadBalancer(t *testing.T) {
@@ -273,6 +404,8 @@ func TestCache_listenLoadBalancer(t *testing.T) {
 	c.Equal([]string{"oahu"}, lb.StickyOptions.StickyOrigins)
 	c.Equal("5f62b7d8be3591c4dea8566a", lb.Applications[0].ID)
 
+	readerMock.On("ReadLoadBalancerRemoval", "123").Return((*types.LoadBalancerRemoval)(nil), nil)
+
 	readerMock.lMock.MockEvent(repository.ActionUpdate, repository.ActionUpdate, &repository.LoadBalancer{
 		ID:     "123",
 		UserID: "user_id_12345",
@@ -304,6 +437,80 @@ func TestCache_listenLoadBalancer(t *testing.T) {
 	c.Empty(lbs)
 }
 
+func TestCache_listenLoadBalancerRestoreAndPurge(t *testing.T) {
+	c := require.New(t)
+
+	readerMock := NewReaderMock()
+	cache := newMockCache(readerMock)
+
+	c.Nil(cache.GetLoadBalancerRemoval("60ecb2bf67774900350d9c42"))
+
+	removedAt := time.Date(2022, time.July, 21, 0, 0, 0, 0, time.UTC)
+
+	readerMock.On("ReadLoadBalancerRemoval", "60ecb2bf67774900350d9c42").Return(&types.LoadBalancerRemoval{
+		LoadBalancerID: "60ecb2bf67774900350d9c42",
+		UserID:         "60ecb35fts687463gh2h72gs",
+		RemovedAt:      removedAt,
+	}, nil).Once()
+
+	readerMock.lMock.MockEvent(repository.ActionUpdate, repository.ActionUpdate, &repository.LoadBalancer{
+		ID:     "60ecb2bf67774900350d9c42",
+		UserID: "",
+	})
+
+	time.Sleep(1 * time.Second) // need time for cache refresh
+
+	c.Empty(cache.GetLoadBalancersByUserID("60ecb35fts687463gh2h72gs"))
+	c.Equal(removedAt, cache.GetLoadBalancerRemoval("60ecb2bf67774900350d9c42").RemovedAt)
+
+	// the update is applied even if the removal cannot be read, the cached removal is kept
+	readerMock.On("ReadLoadBalancerRemoval", "60ecb2bf67774900350d9c42").Return((*types.LoadBalancerRemoval)(nil), errors.New("dummy error")).Once()
+
+	readerMock.lMock.MockEvent(repository.ActionUpdate, repository.ActionUpdate, &repository.LoadBalancer{
+		ID:     "60ecb2bf67774900350d9c42",
+		Name:   "renamed",
+		UserID: "",
+	})
+
+	time.Sleep(1 * time.Second) // need time for cache refresh
+
+	c.Equal("renamed", cache.GetLoadBalancer("60ecb2bf67774900350d9c42").Name)
+	c.Equal(removedAt, cache.GetLoadBalancerRemoval("60ecb2bf67774900350d9c42").RemovedAt)
+
+	readerMock.On("ReadLoadBalancerRemoval", "60ecb2bf67774900350d9c42").Return((*types.LoadBalancerRemoval)(nil), nil).Once()
+
+	readerMock.lMock.MockEvent(repository.ActionUpdate, repository.ActionUpdate, &repository.LoadBalancer{
+		ID:     "60ecb2bf67774900350d9c42",
+		UserID: "60ecb35fts687463gh2h72gs",
+	})
+
+	time.Sleep(1 * time.Second) // need time for cache refresh
+
+	c.Len(cache.GetLoadBalancersByUserID("60ecb35fts687463gh2h72gs"), 1)
+	c.Equal("60ecb35fts687463gh2h72gs", cache.GetLoadBalancer("60ecb2bf67774900350d9c42").UserID)
+	c.Nil(cache.GetLoadBalancerRemoval("60ecb2bf67774900350d9c42"))
+
+	generation := cache.GetCollectionVersion(CollectionLoadBalancers).Generation
+
+	readerMock.lMock.MockEvent(actionDelete, actionDelete, &repository.LoadBalancer{
+		ID: "60ecb2bf67774900350d9c42",
+	})
+
+	time.Sleep(1 * time.Second) // need time for cache refresh
+
+	c.Nil(cache.GetLoadBalancer("60ecb2bf67774900350d9c42"))
+	c.Empty(cache.GetLoadBalancersByUserID("60ecb35fts687463gh2h72gs"))
+
+	for _, lb := range cache.GetLoadBalancers() {
+		c.NotEqual("60ecb2bf67774900350d9c42", lb.ID)
+	}
+
+	changes := cache.GetChangesSince(generation)
+	c.Len(changes.Removed, 1)
+	c.Equal(CollectionLoadBalancers, changes.Removed[0].Collection)
+	c.Equal("60ecb2bf67774900350d9c42", changes.Removed[0].ID)
+}
+
 func TestCache_listenRedirect(t *testing.T) {
 	c := require.New(t)
 
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"github.com/pokt-foundation/pocket-http-db/types"
 	postgresdriver "github.com/pokt-foundation/portal-api-go/postgres-driver"
 	"github.com/pokt-foundation/portal-api-go/repository"
 	"github.com/stretchr/testify/mock"
@@ -42,6 +43,18 @@ func (r *ReaderMock) ReadLoadBalancers() ([]*repository.LoadBalancer, error) {
 	return args.Get(0).([]*repository.LoadBalancer), args.Error(1)
 }
 
+func (r *ReaderMock) ReadLoadBalancerRemovals() ([]*types.LoadBalancerRemoval, error) {
+	args := r.Called()
+
+	return args.Get(0).([]*types.LoadBalancerRemoval), args.Error(1)
+}
+
+func (r *ReaderMock) ReadLoadBalancerRemoval(id string) (*types.LoadBalancerRemoval, error) {
+	args := r.Called(id)
+
+	return args.Get(0).(*types.LoadBalancerRemoval), args.Error(1)
+}
+
 func (r *ReaderMock) ReadPayPlans() ([]*repository.PayPlan, error) {
 	args := r.Called()
 
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// Collection identifies a group of cached entities sharing a version
+type Collection string
+
+const (
+	CollectionApplications  Collection = "applications"
+	CollectionBlockchains   Collection = "blockchains"
+	CollectionLoadBalancers Collection = "load_balancers"
+	CollectionPayPlans      Collection = "pay_plans"
+	CollectionRedirects     Collection = "redirects"
+)
+
+// Version represents the cache generation in which an entity or collection last changed
+type Version struct {
+	Generation uint64
+	UpdatedAt  time.Time
+}
+
+func maxVersion(a, b Version) Version {
+	if b.Generation > a.Generation {
+		return b
+	}
+
+	return a
+}
+
+// GetCollectionVersion returns the latest version among the given collections
+func (c *Cache) GetCollectionVersion(collections ...Collection) Version {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	var version Version
+
+	for _, collection := range collections {
+		version = maxVersion(version, c.collectionVersions[collection])
+	}
+
+	return version
+}
+
+// GetEntityVersion returns the version of the entity with the given ID, load balancers
+// also take into account the version of the applications they contain
+func (c *Cache) GetEntityVersion(collection Collection, id string) Version {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	return c.entityVersion(collection, id)
+}
+
+// entityVersion returns the entity version, callers must hold the lock
+func (c *Cache) entityVersion(collection Collection, id string) Version {
+	version := c.entityVersions[collection][id]
+
+	if collection == CollectionLoadBalancers {
+		if lb := c.loadBalancersMap[id]; lb != nil {
+			for _, app := range lb.Applications {
+				if app != nil {
+					version = maxVersion(version, c.entityVersions[CollectionApplications][app.ID])
+				}
+			}
+		}
+	}
+
+	return version
+}
+
+// BumpVersion moves the entities to a new version, for callers changing cached entities in place
+func (c *Cache) BumpVersion(collection Collection, ids ...string) {
+	c.rwMutex.Lock()
+	defer c.rwMutex.Unlock()
+
+	c.bumpVersion(collection, ids...)
+}
+
+// bumpVersion moves the cache to a new generation and assigns it to the collection
+// and the given entities, callers must hold the write lock
+func (c *Cache) bumpVersion(collection Collection, ids ...string) {
+	c.generation++
+
+	version := Version{
+		Generation: c.generation,
+		UpdatedAt:  time.Now(),
+	}
+
+	c.collectionVersions[collection] = version
+
+	entities := c.entityVersions[collection]
+	if entities == nil {
+		entities = make(map[string]Version)
+		c.entityVersions[collection] = entities
+	}
+
+	for _, id := range ids {
+		entities[id] = version
+	}
+}
+
+// trackChanges bumps the versions of the entities that changed between two loads of a
+// collection and logs the removal of the ones no longer present, callers must hold the write lock
+func (c *Cache) trackChanges(collection Collection, changed, removed []string) {
+	if len(changed) == 0 && len(removed) == 0 {
+		return
+	}
+
+	c.bumpVersion(collection, changed...)
+
+	for _, id := range removed {
+		delete(c.entityVersions[collection], id)
+
+		c.removals.add(Removal{
+			Collection: collection,
+			ID:         id,
+			Generation: c.generation,
+		})
+	}
+}
+
+// diffEntities returns the keys of the entities added or modified in newMap and the
+// ones missing from it compared to oldMap, entities are compared by their JSON content
+// as values read from the database do not keep pointer or location equality
+func diffEntities[K ~string, T any](oldMap, newMap map[K]*T) ([]string, []string) {
+	changed := []string{}
+	removed := []string{}
+
+	for key, newEntity := range newMap {
+		oldEntity, ok := oldMap[key]
+		if !ok || !sameContent(oldEntity, newEntity) {
+			changed = append(changed, string(key))
+		}
+	}
+
+	for key := range oldMap {
+		if _, ok := newMap[key]; !ok {
+			removed = append(removed, string(key))
+		}
+	}
+
+	return changed, removed
+}
+
+func sameContent(a, b any) bool {
+	rawA, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	rawB, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(rawA, rawB)
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+	"github.com/pokt-foundation/portal-api-go/repository"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_Versions(t *testing.T) {
+	c := require.New(t)
+
+	readerMock := &ReaderMock{}
+
+	readerMock.On("ReadApplications").Return([]*repository.Application{
+		{ID: "5f62b7d8be3591c4dea8566d", Name: "pablo"},
+		{ID: "5f62b7d8be3591c4dea8566a"},
+	}, nil).Once()
+
+	readerMock.On("ReadLoadBalancerRemovals").Return([]*types.LoadBalancerRemoval{}, nil)
+
+	readerMock.On("ReadLoadBalancers").Return([]*repository.LoadBalancer{
+		{ID: "60ecb2bf67774900350d9c42", ApplicationIDs: []string{"5f62b7d8be3591c4dea8566d"}},
+	}, nil)
+
+	cache := NewCache(readerMock, logrus.New())
+
+	c.Zero(cache.GetCollectionVersion(CollectionApplications).Generation)
+
+	err := cache.setApplications()
+	c.NoError(err)
+
+	err = cache.setLoadBalancers()
+	c.NoError(err)
+
+	appsVersion := cache.GetCollectionVersion(CollectionApplications)
+	c.NotZero(appsVersion.Generation)
+	c.False(appsVersion.UpdatedAt.IsZero())
+
+	appVersion := cache.GetEntityVersion(CollectionApplications, "5f62b7d8be3591c4dea8566a")
+	lbVersion := cache.GetEntityVersion(CollectionLoadBalancers, "60ecb2bf67774900350d9c42")
+
+	// Reloading unchanged content keeps every version
+	readerMock.On("ReadApplications").Return([]*repository.Application{
+		{ID: "5f62b7d8be3591c4dea8566d", Name: "pablo"},
+		{ID: "5f62b7d8be3591c4dea8566a"},
+	}, nil).Once()
+
+	err = cache.setApplications()
+	c.NoError(err)
+
+	c.Equal(appsVersion, cache.GetCollectionVersion(CollectionApplications))
+
+	// Updates bump the entity, its collection and the load balancers holding it
+	cache.updateApplication(repository.Application{ID: "5f62b7d8be3591c4dea8566d", UserID: "60ecb2bf67774900350d9c43", Name: "orlando"})
+
+	newAppsVersion := cache.GetCollectionVersion(CollectionApplications)
+	c.Greater(newAppsVersion.Generation, appsVersion.Generation)
+	c.Equal(appVersion, cache.GetEntityVersion(CollectionApplications, "5f62b7d8be3591c4dea8566a"))
+	c.Equal(newAppsVersion, cache.GetEntityVersion(CollectionApplications, "5f62b7d8be3591c4dea8566d"))
+	c.Equal(newAppsVersion, cache.GetEntityVersion(CollectionLoadBalancers, "60ecb2bf67774900350d9c42"))
+	c.Equal(lbVersion, cache.GetCollectionVersion(CollectionLoadBalancers))
+	c.Equal(newAppsVersion, cache.GetCollectionVersion(CollectionLoadBalancers, CollectionApplications))
+
+	// Reloading with a removed entity bumps only the collection
+	readerMock.On("ReadApplications").Return([]*repository.Application{
+		{ID: "5f62b7d8be3591c4dea8566a"},
+	}, nil).Once()
+
+	err = cache.setApplications()
+	c.NoError(err)
+
+	c.Greater(cache.GetCollectionVersion(CollectionApplications).Generation, newAppsVersion.Generation)
+	c.Equal(appVersion, cache.GetEntityVersion(CollectionApplications, "5f62b7d8be3591c4dea8566a"))
+	c.Zero(cache.GetEntityVersion(CollectionApplications, "5f62b7d8be3591c4dea8566d").Generation)
+}
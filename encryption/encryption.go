@@ -0,0 +1,182 @@
+// Package encryption provides envelope encryption for the secrets PHD stores at rest
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// prefix marks the values encrypted by an Encrypter, values without it are treated as plaintext
+	prefix = "enc:v1:"
+
+	dataKeySize = 32
+)
+
+var (
+	ErrMalformedValue = errors.New("malformed encrypted value")
+	ErrUnknownKey     = errors.New("unknown encryption key")
+)
+
+// KeyProvider represents the source of the key encryption keys, it wraps the per value data
+// keys so the master keys never leave the provider, allowing local or KMS style implementations
+type KeyProvider interface {
+	// CurrentKeyID returns the ID of the key used to wrap new data keys
+	CurrentKeyID() string
+	WrapKey(dataKey []byte) (keyID string, wrappedKey []byte, err error)
+	UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// Encrypter encrypts and decrypts values with a fresh data key per value wrapped by a KeyProvider
+type Encrypter struct {
+	provider KeyProvider
+}
+
+// NewEncrypter returns Encrypter instance using the given key provider
+func NewEncrypter(provider KeyProvider) *Encrypter {
+	return &Encrypter{
+		provider: provider,
+	}
+}
+
+// Encrypt returns the encrypted form of the value, empty values are kept empty
+func (e *Encrypter) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, dataKeySize)
+
+	_, err := io.ReadFull(rand.Reader, dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	keyID, wrappedKey, err := e.provider.WrapKey(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + strings.Join([]string{
+		keyID,
+		base64.RawStdEncoding.EncodeToString(wrappedKey),
+		base64.RawStdEncoding.EncodeToString(sealed),
+	}, ":"), nil
+}
+
+// Decrypt returns the plaintext of a value returned by Encrypt, values that are not
+// encrypted are returned as they are so existing plaintext rows keep working
+func (e *Encrypter) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, wrappedKey, sealed, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := e.provider.UnwrapKey(keyID, wrappedKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataKey, sealed)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsReencryption reports whether the value is plaintext or was encrypted with a key other than the current one
+func (e *Encrypter) NeedsReencryption(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	if !IsEncrypted(value) {
+		return true
+	}
+
+	keyID, _, _, err := parse(value)
+
+	return err != nil || keyID != e.provider.CurrentKeyID()
+}
+
+// IsEncrypted reports whether the value has the encrypted value format
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+func parse(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", nil, nil, ErrMalformedValue
+	}
+
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("%w: %s", ErrMalformedValue, err)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("%w: %s", ErrMalformedValue, err)
+	}
+
+	return parts[0], wrappedKey, sealed, nil
+}
+
+// seal encrypts the plaintext with AES-GCM returning the nonce followed by the ciphertext
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts a value returned by seal
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformedValue
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
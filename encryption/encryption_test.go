@@ -0,0 +1,166 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testKey1 = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("1", dataKeySize)))
+	testKey2 = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("2", dataKeySize)))
+)
+
+func writeKeyFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	require.NoError(t, err)
+
+	return path
+}
+
+func newTestEncrypter(t *testing.T, currentKeyID string, keys map[string]string) *Encrypter {
+	t.Helper()
+
+	rawKeys := make([]string, 0, len(keys))
+	for keyID, key := range keys {
+		rawKeys = append(rawKeys, fmt.Sprintf("%q: %q", keyID, key))
+	}
+
+	provider, err := NewLocalKeyProvider(writeKeyFile(t,
+		fmt.Sprintf(`{"currentKeyID": %q, "keys": {%s}}`, currentKeyID, strings.Join(rawKeys, ","))))
+	require.NoError(t, err)
+
+	return NewEncrypter(provider)
+}
+
+func TestEncrypter_RoundTrip(t *testing.T) {
+	c := require.New(t)
+
+	encrypter := newTestEncrypter(t, "k1", map[string]string{"k1": testKey1})
+
+	encrypted, err := encrypter.Encrypt("super-secret")
+	c.NoError(err)
+	c.True(IsEncrypted(encrypted))
+	c.NotContains(encrypted, "super-secret")
+
+	otherEncrypted, err := encrypter.Encrypt("super-secret")
+	c.NoError(err)
+	c.NotEqual(encrypted, otherEncrypted)
+
+	decrypted, err := encrypter.Decrypt(encrypted)
+	c.NoError(err)
+	c.Equal("super-secret", decrypted)
+
+	empty, err := encrypter.Encrypt("")
+	c.NoError(err)
+	c.Empty(empty)
+
+	plaintext, err := encrypter.Decrypt("not-encrypted")
+	c.NoError(err)
+	c.Equal("not-encrypted", plaintext)
+}
+
+func TestEncrypter_KeyRotation(t *testing.T) {
+	c := require.New(t)
+
+	oldEncrypter := newTestEncrypter(t, "k1", map[string]string{"k1": testKey1})
+
+	encrypted, err := oldEncrypter.Encrypt("super-secret")
+	c.NoError(err)
+	c.False(oldEncrypter.NeedsReencryption(encrypted))
+
+	encrypter := newTestEncrypter(t, "k2", map[string]string{"k1": testKey1, "k2": testKey2})
+
+	c.True(encrypter.NeedsReencryption(encrypted))
+	c.True(encrypter.NeedsReencryption("plaintext"))
+	c.False(encrypter.NeedsReencryption(""))
+
+	decrypted, err := encrypter.Decrypt(encrypted)
+	c.NoError(err)
+	c.Equal("super-secret", decrypted)
+
+	reencrypted, err := encrypter.Encrypt(decrypted)
+	c.NoError(err)
+	c.False(encrypter.NeedsReencryption(reencrypted))
+
+	_, err = oldEncrypter.Decrypt(reencrypted)
+	c.ErrorIs(err, ErrUnknownKey)
+}
+
+func TestEncrypter_Decrypt_Malformed(t *testing.T) {
+	c := require.New(t)
+
+	encrypter := newTestEncrypter(t, "k1", map[string]string{"k1": testKey1})
+
+	_, err := encrypter.Decrypt(prefix + "k1:only-two")
+	c.ErrorIs(err, ErrMalformedValue)
+
+	_, err = encrypter.Decrypt(prefix + "k1:!!!:!!!")
+	c.ErrorIs(err, ErrMalformedValue)
+
+	encrypted, err := encrypter.Encrypt("super-secret")
+	c.NoError(err)
+
+	_, err = encrypter.Decrypt(encrypted[:len(encrypted)-4])
+	c.Error(err)
+}
+
+func TestNewLocalKeyProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     error
+	}{
+		{
+			name:    "Valid key file",
+			content: fmt.Sprintf(`{"currentKeyID": "k1", "keys": {"k1": %q}}`, testKey1),
+		},
+		{
+			name:    "Invalid JSON",
+			content: `{"currentKeyID"`,
+			err:     errInvalidKeyFile,
+		},
+		{
+			name:    "Short key",
+			content: fmt.Sprintf(`{"currentKeyID": "k1", "keys": {"k1": %q}}`, base64.StdEncoding.EncodeToString([]byte("short"))),
+			err:     errInvalidKeyFile,
+		},
+		{
+			name:    "Missing current key",
+			content: fmt.Sprintf(`{"currentKeyID": "k2", "keys": {"k1": %q}}`, testKey1),
+			err:     errInvalidKeyFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := require.New(t)
+
+			provider, err := NewLocalKeyProvider(writeKeyFile(t, tt.content))
+			c.ErrorIs(err, tt.err)
+
+			if tt.err == nil {
+				c.Equal("k1", provider.CurrentKeyID())
+			}
+
+			provider, err = NewLocalKeyProviderFromJSON([]byte(tt.content))
+			c.ErrorIs(err, tt.err)
+
+			if tt.err == nil {
+				c.Equal("k1", provider.CurrentKeyID())
+			}
+		})
+	}
+
+	_, err := NewLocalKeyProvider(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
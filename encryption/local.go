@@ -0,0 +1,90 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var errInvalidKeyFile = errors.New("invalid key file")
+
+// keyFile is the format of the local key file, keys are base64 encoded 32 bytes AES keys
+type keyFile struct {
+	CurrentKeyID string            `json:"currentKeyID"`
+	Keys         map[string]string `json:"keys"`
+}
+
+// LocalKeyProvider wraps data keys with master keys loaded from a local file
+type LocalKeyProvider struct {
+	currentKeyID string
+	keys         map[string][]byte
+}
+
+// NewLocalKeyProvider returns LocalKeyProvider instance from the key file at the given path,
+// the file holds every key still needed to decrypt and the one used for new values
+func NewLocalKeyProvider(path string) (*LocalKeyProvider, error) {
+	rawFile, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLocalKeyProviderFromJSON(rawFile)
+}
+
+// NewLocalKeyProviderFromJSON returns LocalKeyProvider instance from the content of a key file,
+// for keys injected as a secret instead of mounted as a file
+func NewLocalKeyProviderFromJSON(rawFile []byte) (*LocalKeyProvider, error) {
+	var file keyFile
+
+	err := json.Unmarshal(rawFile, &file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidKeyFile, err)
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+
+	for keyID, rawKey := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(rawKey)
+		if err != nil || len(key) != dataKeySize {
+			return nil, fmt.Errorf("%w: key %s must be %d base64 encoded bytes", errInvalidKeyFile, keyID, dataKeySize)
+		}
+
+		keys[keyID] = key
+	}
+
+	if _, ok := keys[file.CurrentKeyID]; !ok {
+		return nil, fmt.Errorf("%w: current key %q not found", errInvalidKeyFile, file.CurrentKeyID)
+	}
+
+	return &LocalKeyProvider{
+		currentKeyID: file.CurrentKeyID,
+		keys:         keys,
+	}, nil
+}
+
+// CurrentKeyID returns the ID of the key used to wrap new data keys
+func (p *LocalKeyProvider) CurrentKeyID() string {
+	return p.currentKeyID
+}
+
+// WrapKey encrypts the data key with the current master key
+func (p *LocalKeyProvider) WrapKey(dataKey []byte) (string, []byte, error) {
+	wrappedKey, err := seal(p.keys[p.currentKeyID], dataKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return p.currentKeyID, wrappedKey, nil
+}
+
+// UnwrapKey decrypts a data key wrapped with the given master key
+func (p *LocalKeyProvider) UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+
+	return open(key, wrappedKey)
+}
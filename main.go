@@ -3,10 +3,13 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/lib/pq"
+	"github.com/pokt-foundation/pocket-http-db/encryption"
+	"github.com/pokt-foundation/pocket-http-db/postgres"
 	"github.com/pokt-foundation/pocket-http-db/router"
 	postgresdriver "github.com/pokt-foundation/portal-api-go/postgres-driver"
 	"github.com/pokt-foundation/utils-go/environment"
@@ -14,28 +17,85 @@ import (
 )
 
 const (
-	connectionString = "CONNECTION_STRING"
-	apiKeys          = "API_KEYS"
-	cacheRefresh     = "CACHE_REFRESH"
-	port             = "PORT"
+	connectionString   = "CONNECTION_STRING"
+	apiKeys            = "API_KEYS"
+	apiKeyCapabilities = "API_KEY_CAPABILITIES"
+	appGracePeriod     = "APPLICATION_GRACE_PERIOD_DAYS"
+	cacheRefresh       = "CACHE_REFRESH"
+	encryptionKeyFile  = "ENCRYPTION_KEY_FILE"
+	encryptionKeys     = "ENCRYPTION_KEYS"
+	idempotencyTTL     = "IDEMPOTENCY_KEY_TTL_HOURS"
+	idempotencyLock    = "IDEMPOTENCY_KEY_LOCK_TIMEOUT_SECONDS"
+	lbGracePeriod      = "LOAD_BALANCER_GRACE_PERIOD_DAYS"
+	payPlanAppQuotas   = "PAY_PLAN_APPLICATION_QUOTAS"
+	port               = "PORT"
+	userAppQuota       = "USER_APPLICATION_QUOTA"
+	userLBQuota        = "USER_LOAD_BALANCER_QUOTA"
+	userQuotas         = "USER_QUOTAS"
+
+	migrateCommand           = "migrate"
+	reencryptCommand         = "reencrypt"
+	migrateWhitelistsCommand = "migrate_whitelists"
 
 	defaultCacheRefreshMinutes = 10
+	defaultGracePeriodDays     = 30
+	defaultIdempotencyTTLHours = 24
+	defaultIdempotencyLockSecs = 60
+	reaperIntervalMinutes      = 60
 	defaultPort                = "8080"
 )
 
 type options struct {
-	connectionString string
-	apiKeys          map[string]bool
-	cacheRefresh     int64
-	port             string
+	connectionString   string
+	apiKeys            map[string]bool
+	apiKeyCapabilities map[string]map[router.Capability]bool
+	appGracePeriod     int64
+	cacheRefresh       int64
+	encryptionKeyFile  string
+	encryptionKeys     string
+	idempotencyTTL     int64
+	idempotencyLock    int64
+	lbGracePeriod      int64
+	port               string
+	quotas             router.Quotas
 }
 
 func gatherOptions() options {
+	capabilities, err := router.ParseCapabilities(environment.GetString(apiKeyCapabilities, ""))
+	if err != nil {
+		panic(err)
+	}
+
+	payPlanQuotas, err := router.ParsePayPlanQuotas(environment.GetString(payPlanAppQuotas, ""))
+	if err != nil {
+		panic(err)
+	}
+
+	perUserQuotas, err := router.ParseUserQuotas(environment.GetString(userQuotas, ""))
+	if err != nil {
+		panic(err)
+	}
+
 	return options{
-		connectionString: environment.MustGetString(connectionString),
-		apiKeys:          environment.MustGetStringMap(apiKeys, ","),
-		cacheRefresh:     environment.GetInt64(cacheRefresh, defaultCacheRefreshMinutes),
-		port:             environment.GetString(port, defaultPort),
+		connectionString:   environment.MustGetString(connectionString),
+		apiKeys:            environment.MustGetStringMap(apiKeys, ","),
+		apiKeyCapabilities: capabilities,
+		appGracePeriod:     environment.GetInt64(appGracePeriod, defaultGracePeriodDays),
+		cacheRefresh:       environment.GetInt64(cacheRefresh, defaultCacheRefreshMinutes),
+		encryptionKeyFile:  environment.GetString(encryptionKeyFile, ""),
+		encryptionKeys:     environment.GetString(encryptionKeys, ""),
+		idempotencyTTL:     environment.GetInt64(idempotencyTTL, defaultIdempotencyTTLHours),
+		idempotencyLock:    environment.GetInt64(idempotencyLock, defaultIdempotencyLockSecs),
+		lbGracePeriod:      environment.GetInt64(lbGracePeriod, defaultGracePeriodDays),
+		port:               environment.GetString(port, defaultPort),
+		quotas: router.Quotas{
+			Default: router.Quota{
+				Applications:  int(environment.GetInt64(userAppQuota, 0)),
+				LoadBalancers: int(environment.GetInt64(userLBQuota, 0)),
+			},
+			PayPlans: payPlanQuotas,
+			Users:    perUserQuotas,
+		},
 	}
 }
 
@@ -50,6 +110,43 @@ func cacheHandler(router *router.Router, cacheRefresh int64, log *logrus.Logger)
 	}
 }
 
+// reaperHandler purges the applications and load balancers whose grace period expired along with the
+// expired idempotency keys, every instance runs it as the purge skips the entities another instance is already purging
+func reaperHandler(driver *postgres.Driver, options options, log *logrus.Logger) {
+	purges := []struct {
+		entity      string
+		gracePeriod int64
+		purge       func(gracePeriod time.Duration) ([]string, error)
+	}{
+		{entity: "applications", gracePeriod: options.appGracePeriod, purge: driver.PurgeExpiredApplications},
+		{entity: "load balancers", gracePeriod: options.lbGracePeriod, purge: driver.PurgeExpiredLoadBalancers},
+	}
+
+	for {
+		for _, p := range purges {
+			purged, err := p.purge(time.Duration(p.gracePeriod) * 24 * time.Hour)
+			if err != nil {
+				log.WithFields(logrus.Fields{"err": err.Error()}).Error(err)
+			}
+
+			if len(purged) > 0 {
+				log.WithFields(logrus.Fields{"ids": purged}).Infof("purged expired %s", p.entity)
+			}
+		}
+
+		purgedKeys, err := driver.PurgeExpiredIdempotencyKeys(time.Duration(options.idempotencyTTL) * time.Hour)
+		if err != nil {
+			log.WithFields(logrus.Fields{"err": err.Error()}).Error(err)
+		}
+
+		if purgedKeys > 0 {
+			log.Infof("purged %d expired idempotency keys", purgedKeys)
+		}
+
+		time.Sleep(reaperIntervalMinutes * time.Minute)
+	}
+}
+
 func httpHandler(router *router.Router, port string, log *logrus.Logger) {
 	http.Handle("/", router.Router)
 
@@ -57,6 +154,72 @@ func httpHandler(router *router.Router, port string, log *logrus.Logger) {
 	log.Fatal(http.ListenAndServe(":"+port, nil))
 }
 
+// newEncrypter returns the encrypter for the secrets at rest from the keys given as they are or in a key
+// file, nil when no keys are configured, keys that cannot be loaded stop the service from starting
+func newEncrypter(keyFile, keys string, log *logrus.Logger) *encryption.Encrypter {
+	var provider *encryption.LocalKeyProvider
+
+	var err error
+
+	switch {
+	case keys != "" && keyFile != "":
+		err = fmt.Errorf("only one of %s and %s can be set", encryptionKeys, encryptionKeyFile)
+	case keys != "":
+		provider, err = encryption.NewLocalKeyProviderFromJSON([]byte(keys))
+		if err != nil {
+			err = fmt.Errorf("load keys from %s: %w", encryptionKeys, err)
+		}
+	case keyFile != "":
+		provider, err = encryption.NewLocalKeyProvider(keyFile)
+		if err != nil {
+			err = fmt.Errorf("load key file %q set in %s, it must be mounted in the container: %w",
+				keyFile, encryptionKeyFile, err)
+		}
+	default:
+		return nil
+	}
+
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err.Error()}).Fatal(err)
+	}
+
+	return encryption.NewEncrypter(provider)
+}
+
+// migrate creates the tables and triggers PHD needs, it must run before starting a new version
+func migrate(driver *postgres.Driver, log *logrus.Logger) {
+	err := driver.Migrate()
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err.Error()}).Fatal(err)
+	}
+
+	log.Println("Migrated the database")
+}
+
+// reencrypt encrypts with the current key every secret not encrypted with it yet
+func reencrypt(driver *postgres.Driver, log *logrus.Logger) {
+	updated, err := driver.ReencryptSecrets()
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err.Error()}).Fatal(err)
+	}
+
+	log.Printf("Re-encrypted %d secrets\n", updated)
+}
+
+// migrateWhitelists moves the contracts and methods whitelists kept in the gateway settings to their own tables
+func migrateWhitelists(driver *postgres.Driver, log *logrus.Logger) {
+	migration, err := driver.MigrateWhitelists()
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err.Error()}).Fatal(err)
+	}
+
+	for appID, reason := range migration.Invalid {
+		log.WithFields(logrus.Fields{"applicationID": appID, "err": reason}).Warn("whitelists left untouched")
+	}
+
+	log.Printf("Migrated the whitelists of %d applications\n", migration.Migrated)
+}
+
 func main() {
 	log := logrus.New()
 	// log as JSON instead of the default ASCII formatter.
@@ -72,22 +235,43 @@ func main() {
 
 	listener := pq.NewListener(options.connectionString, 10*time.Second, time.Minute, reportProblem)
 
-	driver, err := postgresdriver.NewPostgresDriverFromConnectionString(options.connectionString, listener)
+	pgDriver, err := postgresdriver.NewPostgresDriverFromConnectionString(options.connectionString, listener)
 	if err != nil {
 		panic(err)
 	}
 
-	router, err := router.NewRouter(driver, driver, options.apiKeys, log)
+	driver := postgres.NewDriver(pgDriver, newEncrypter(options.encryptionKeyFile, options.encryptionKeys, log), log)
+
+	if len(os.Args) > 1 && os.Args[1] == migrateCommand {
+		migrate(driver, log)
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == reencryptCommand {
+		reencrypt(driver, log)
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == migrateWhitelistsCommand {
+		migrateWhitelists(driver, log)
+		return
+	}
+
+	router, err := router.NewRouter(driver, driver, options.apiKeys, options.apiKeyCapabilities, options.quotas, log)
 	if err != nil {
 		panic(err)
 	}
 
+	router.IdempotencyTTL = time.Duration(options.idempotencyTTL) * time.Hour
+	router.IdempotencyLockTimeout = time.Duration(options.idempotencyLock) * time.Second
+
 	var wg sync.WaitGroup
 
 	wg.Add(1)
 
 	go httpHandler(router, options.port, log)
 	go cacheHandler(router, options.cacheRefresh, log)
+	go reaperHandler(driver, options, log)
 
 	wg.Wait()
 }
@@ -0,0 +1,340 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+	postgresdriver "github.com/pokt-foundation/portal-api-go/postgres-driver"
+	"github.com/pokt-foundation/portal-api-go/repository"
+)
+
+const (
+	updateApplicationScript = `
+	UPDATE applications
+	SET name = COALESCE($1, name), status = COALESCE($2, status), first_date_surpassed = COALESCE($3, first_date_surpassed),
+	updated_at = $4
+	WHERE application_id = $5`
+	updateGatewayAATPrivateKeyScript     = `UPDATE gateway_aat SET private_key = $1 WHERE application_id = $2`
+	updateGatewaySettingsSecretKeyScript = `UPDATE gateway_settings SET secret_key = $1 WHERE application_id = $2`
+	upsertGatewaySettingsSecretKeyScript = `
+	INSERT into gateway_settings (application_id, secret_key)
+	VALUES ($1, $2)
+	ON CONFLICT (application_id)
+	DO UPDATE SET secret_key = EXCLUDED.secret_key`
+	upsertGatewayAATScript = `
+	INSERT into gateway_aat (application_id, address, client_public_key, private_key, public_key, signature, version)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (application_id)
+	DO UPDATE SET address = EXCLUDED.address, client_public_key = EXCLUDED.client_public_key, private_key = EXCLUDED.private_key,
+	public_key = EXCLUDED.public_key, signature = EXCLUDED.signature, version = EXCLUDED.version`
+)
+
+// ReadApplications returns all applications on the database with their whitelists read from
+// their tables and their secrets decrypted
+func (d *Driver) ReadApplications() ([]*repository.Application, error) {
+	apps, err := d.PostgresDriver.ReadApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := readWhitelists(d, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range apps {
+		stored.apply(app.ID, &app.GatewaySettings)
+	}
+
+	if d.encrypter == nil {
+		return apps, nil
+	}
+
+	// secrets that cannot be decrypted are blanked as in notifications, so one bad
+	// secret does not keep the cache from loading
+	for _, app := range apps {
+		app.GatewayAAT.PrivateKey, err = d.encrypter.Decrypt(app.GatewayAAT.PrivateKey)
+		if err != nil {
+			app.GatewayAAT.PrivateKey = ""
+			d.logError(fmt.Errorf("decrypt private key of application %s failed: %w", app.ID, err))
+		}
+
+		app.GatewaySettings.SecretKey, err = d.encrypter.Decrypt(app.GatewaySettings.SecretKey)
+		if err != nil {
+			app.GatewaySettings.SecretKey = ""
+			d.logError(fmt.Errorf("decrypt secret key of application %s failed: %w", app.ID, err))
+		}
+	}
+
+	return apps, nil
+}
+
+// WriteApplication saves input application in the database with its secrets encrypted,
+// the application returned keeps the secrets in plaintext
+func (d *Driver) WriteApplication(app *repository.Application) (*repository.Application, error) {
+	writtenApp, err := d.writeApplication(app)
+	if err != nil {
+		return nil, err
+	}
+
+	// the whitelists are written upstream in the gateway settings, they are read from there until moved
+	settings := writtenApp.GatewaySettings
+	if len(settings.WhitelistContracts) > 0 || len(settings.WhitelistMethods) > 0 {
+		err = d.storeWhitelists(writtenApp.ID, settings)
+		if err != nil {
+			d.logError(fmt.Errorf("move whitelists of application %s failed: %w", writtenApp.ID, err))
+		}
+	}
+
+	return writtenApp, nil
+}
+
+func (d *Driver) writeApplication(app *repository.Application) (*repository.Application, error) {
+	if d.encrypter == nil {
+		return d.PostgresDriver.WriteApplication(app)
+	}
+
+	privateKey, secretKey := app.GatewayAAT.PrivateKey, app.GatewaySettings.SecretKey
+
+	encryptedApp := *app
+
+	var err error
+
+	encryptedApp.GatewayAAT.PrivateKey, err = d.encrypter.Encrypt(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedApp.GatewaySettings.SecretKey, err = d.encrypter.Encrypt(secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	writtenApp, err := d.PostgresDriver.WriteApplication(&encryptedApp)
+	if err != nil {
+		return nil, err
+	}
+
+	writtenApp.GatewayAAT.PrivateKey = privateKey
+	writtenApp.GatewaySettings.SecretKey = secretKey
+
+	return writtenApp, nil
+}
+
+// UpdateApplication updates the fields available in options in a single transaction encrypting the secret key if
+// present, when ifUpdatedAt is given the application is updated only if it still has that updated at, otherwise
+// types.ErrApplicationChanged is returned, returning the updated at the application is left with
+func (d *Driver) UpdateApplication(id string, fieldsToUpdate *repository.UpdateApplication, ifUpdatedAt *time.Time) (time.Time, error) {
+	if id == "" {
+		return time.Time{}, postgresdriver.ErrMissingID
+	}
+
+	if fieldsToUpdate == nil {
+		return time.Time{}, postgresdriver.ErrNoFieldsToUpdate
+	}
+
+	err := fieldsToUpdate.Validate()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := updateTime()
+
+	claimed, err := claimUpdate(tx, claimApplicationUpdateScript, id, ifUpdatedAt, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !claimed {
+		return time.Time{}, types.ErrApplicationChanged
+	}
+
+	_, err = tx.Exec(updateApplicationScript, newSQLNullString(fieldsToUpdate.Name),
+		newSQLNullString(string(fieldsToUpdate.Status)), newSQLNullTime(fieldsToUpdate.FirstDateSurpassed), now, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = d.upsertApplicationSettings(tx, id, fieldsToUpdate.Limit, fieldsToUpdate.GatewaySettings,
+		fieldsToUpdate.NotificationSettings)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return now, nil
+}
+
+// UpdateSecretKey replaces the gateway secret key of the application encrypting it, leaving
+// the rest of the gateway settings untouched
+func (d *Driver) UpdateSecretKey(id, secretKey string) error {
+	if id == "" {
+		return postgresdriver.ErrMissingID
+	}
+
+	encryptedSecretKey, err := d.encrypt(secretKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.Exec(upsertGatewaySettingsSecretKeyScript, id, newSQLNullString(encryptedSecretKey))
+
+	return err
+}
+
+// UpdateGatewayAAT replaces the gateway AAT of the application encrypting its private key
+func (d *Driver) UpdateGatewayAAT(id string, aat *repository.GatewayAAT) error {
+	if id == "" {
+		return postgresdriver.ErrMissingID
+	}
+
+	privateKey, err := d.encrypt(aat.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.Exec(upsertGatewayAATScript, id, newSQLNullString(aat.Address), newSQLNullString(aat.ClientPublicKey),
+		newSQLNullString(privateKey), newSQLNullString(aat.ApplicationPublicKey), newSQLNullString(aat.ApplicationSignature),
+		newSQLNullString(aat.Version))
+
+	return err
+}
+
+// NotificationChannel returns the database notifications with the whitelists of the gateway settings
+// read from their tables and the secrets they carry decrypted
+func (d *Driver) NotificationChannel() <-chan *repository.Notification {
+	d.notificationsOnce.Do(func() {
+		notifications := make(chan *repository.Notification, 32)
+
+		go func() {
+			for n := range d.PostgresDriver.NotificationChannel() {
+				d.readNotificationWhitelists(n)
+
+				if d.encrypter != nil {
+					d.decryptNotification(n)
+				}
+
+				notifications <- n
+			}
+
+			close(notifications)
+		}()
+
+		d.notifications = notifications
+	})
+
+	return d.notifications
+}
+
+// readNotificationWhitelists sets on gateway settings notifications the whitelists saved in their tables, the
+// notifications are sent once the transaction commits so the tables hold the whitelists of the change by then
+func (d *Driver) readNotificationWhitelists(n *repository.Notification) {
+	if n == nil {
+		return
+	}
+
+	settings, ok := n.Data.(*repository.GatewaySettings)
+	if !ok || settings.ID == "" {
+		return
+	}
+
+	stored, err := readWhitelists(d, settings.ID)
+	if err != nil {
+		d.logError(fmt.Errorf("read whitelists of application %s failed: %w", settings.ID, err))
+		return
+	}
+
+	stored.apply(settings.ID, settings)
+}
+
+// decryptNotification decrypts in place the secrets of a notification, secrets that
+// cannot be decrypted are blanked so ciphertext never reaches the cache
+func (d *Driver) decryptNotification(n *repository.Notification) {
+	if n == nil {
+		return
+	}
+
+	var err error
+
+	switch data := n.Data.(type) {
+	case *repository.GatewayAAT:
+		data.PrivateKey, err = d.encrypter.Decrypt(data.PrivateKey)
+		if err != nil {
+			data.PrivateKey = ""
+			d.logError(fmt.Errorf("decrypt private key of application %s failed: %w", data.ID, err))
+		}
+	case *repository.GatewaySettings:
+		data.SecretKey, err = d.encrypter.Decrypt(data.SecretKey)
+		if err != nil {
+			data.SecretKey = ""
+			d.logError(fmt.Errorf("decrypt secret key of application %s failed: %w", data.ID, err))
+		}
+	}
+}
+
+// ReencryptSecrets encrypts with the current key every secret stored in plaintext or with
+// an older key, returning the number of secrets updated
+func (d *Driver) ReencryptSecrets() (int, error) {
+	if d.encrypter == nil {
+		return 0, errNoEncrypter
+	}
+
+	apps, err := d.PostgresDriver.ReadApplications()
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	updated := 0
+
+	for _, app := range apps {
+		secrets := []struct {
+			value  string
+			script string
+		}{
+			{value: app.GatewayAAT.PrivateKey, script: updateGatewayAATPrivateKeyScript},
+			{value: app.GatewaySettings.SecretKey, script: updateGatewaySettingsSecretKeyScript},
+		}
+
+		for _, secret := range secrets {
+			if !d.encrypter.NeedsReencryption(secret.value) {
+				continue
+			}
+
+			plaintext, err := d.encrypter.Decrypt(secret.value)
+			if err != nil {
+				return 0, fmt.Errorf("decrypt secret of application %s failed: %w", app.ID, err)
+			}
+
+			encrypted, err := d.encrypter.Encrypt(plaintext)
+			if err != nil {
+				return 0, err
+			}
+
+			_, err = tx.Exec(secret.script, encrypted, app.ID)
+			if err != nil {
+				return 0, err
+			}
+
+			updated++
+		}
+	}
+
+	return updated, tx.Commit()
+}
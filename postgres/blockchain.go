@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pokt-foundation/pocket-http-db/types"
+	postgresdriver "github.com/pokt-foundation/portal-api-go/postgres-driver"
+	"github.com/pokt-foundation/portal-api-go/repository"
+)
+
+const (
+	updateBlockchainScript = `
+	UPDATE blockchains
+	SET altruist = $1, chain_id = $2, enforce_result = $3, blockchain_aliases = $4, log_limit_blocks = $5,
+	request_timeout = $6, updated_at = $7
+	WHERE blockchain_id = $8`
+	upsertSyncCheckOptionsScript = `
+	INSERT into sync_check_options (blockchain_id, allowance, body, path, result_key)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (blockchain_id)
+	DO UPDATE SET allowance = EXCLUDED.allowance, body = EXCLUDED.body, path = EXCLUDED.path, result_key = EXCLUDED.result_key`
+)
+
+// UpdateBlockchain replaces the fields available in options in db, empty values clear the stored ones
+// and no sync check options leave the blockchain with empty ones
+func (d *Driver) UpdateBlockchain(id string, fieldsToUpdate *types.UpdateBlockchain) error {
+	if id == "" {
+		return postgresdriver.ErrMissingID
+	}
+
+	invalidUpdate := fieldsToUpdate.Validate()
+	if invalidUpdate != nil {
+		return invalidUpdate
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	_, err = tx.Exec(updateBlockchainScript, newSQLNullString(fieldsToUpdate.Altruist), newSQLNullString(fieldsToUpdate.ChainID),
+		newSQLNullString(fieldsToUpdate.EnforceResult), pq.StringArray(fieldsToUpdate.BlockchainAliases),
+		newSQLNullInt32(int32(fieldsToUpdate.LogLimitBlocks)), newSQLNullInt32(int32(fieldsToUpdate.RequestTimeout)),
+		time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	var opts repository.SyncCheckOptions
+	if fieldsToUpdate.SyncCheckOptions != nil {
+		opts = *fieldsToUpdate.SyncCheckOptions
+	}
+
+	_, err = tx.Exec(upsertSyncCheckOptionsScript, id, newSQLNullInt32(int32(opts.Allowance)),
+		newSQLNullString(opts.Body), newSQLNullString(opts.Path), newSQLNullString(opts.ResultKey))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
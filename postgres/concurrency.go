@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"time"
+)
+
+const (
+	claimApplicationUpdateScript = `
+	UPDATE applications
+	SET updated_at = $1
+	WHERE application_id = $2 AND updated_at IS NOT DISTINCT FROM $3`
+	claimLoadBalancerUpdateScript = `
+	UPDATE loadbalancers
+	SET updated_at = $1
+	WHERE lb_id = $2 AND updated_at IS NOT DISTINCT FROM $3`
+)
+
+// claimUpdate sets the updated at of the entity within the transaction only if it still is the given one, reporting
+// whether it was, a nil updated at claims the entity unconditionally and a zero one matches the rows never updated,
+// as the claim is part of the transaction it is undone along with the write if the write fails
+func claimUpdate(tx execer, script, id string, ifUpdatedAt *time.Time, now time.Time) (bool, error) {
+	if ifUpdatedAt == nil {
+		return true, nil
+	}
+
+	result, err := tx.Exec(script, now, id, newSQLNullTime(ifUpdatedAt.UTC()))
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// updateTime returns the time to save as updated at, truncated to the precision of
+// the database so it matches the updated at read back from it
+func updateTime() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pokt-foundation/pocket-http-db/types"
+	"github.com/pokt-foundation/portal-api-go/repository"
+	"github.com/pokt-foundation/utils-go/random"
+)
+
+// idLength is the number of hex characters of the IDs generated for new entities, as upstream
+const idLength = 24
+
+const (
+	insertEndpointApplicationScript = `
+	INSERT into applications (application_id, user_id, name, contact_email, description, owner, url, status, dummy, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	insertEndpointAppLimitScript = `
+	INSERT into app_limits (application_id, pay_plan, custom_limit)
+	VALUES ($1, $2, $3)`
+	insertEndpointGatewayAATScript = `
+	INSERT into gateway_aat (application_id, address, client_public_key, private_key, public_key, signature, version)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	insertEndpointGatewaySettingsScript = `
+	INSERT into gateway_settings (application_id, secret_key, secret_key_required, whitelist_origins, whitelist_user_agents, whitelist_blockchains)
+	VALUES ($1, $2, $3, $4, $5, $6)`
+	insertEndpointNotificationSettingsScript = `
+	INSERT into notification_settings (application_id, signed_up, on_quarter, on_half, on_three_quarters, on_full)
+	VALUES ($1, $2, $3, $4, $5, $6)`
+	insertEndpointLoadBalancerScript = `
+	INSERT into loadbalancers (lb_id, name, user_id, request_timeout, gigastake, gigastake_redirect, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	insertEndpointStickinessOptionsScript = `
+	INSERT into stickiness_options (lb_id, duration, sticky_max, stickiness, origins)
+	VALUES ($1, $2, $3, $4, $5)`
+	insertEndpointLbAppScript = `
+	INSERT into lb_apps (lb_id, app_id)
+	VALUES ($1, $2)`
+)
+
+// execer is the part of a transaction the inserts need
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+var (
+	ErrNoApplications = errors.New("endpoint has no applications")
+	ErrUserMismatch   = errors.New("application user does not match the load balancer user")
+)
+
+// WriteEndpoint saves the load balancer and its new applications in a single transaction so a failure
+// leaves none of them behind, the endpoint returned holds the generated IDs and the secrets in plaintext
+func (d *Driver) WriteEndpoint(endpoint *types.Endpoint) (*types.Endpoint, error) {
+	if len(endpoint.Applications) == 0 {
+		return nil, ErrNoApplications
+	}
+
+	now := time.Now()
+
+	lb := endpoint.LoadBalancer
+
+	lbID, err := random.HexString(idLength)
+	if err != nil {
+		return nil, err
+	}
+
+	lb.ID = lbID
+	lb.CreatedAt = now
+	lb.UpdatedAt = now
+	lb.ApplicationIDs = append([]string{}, lb.ApplicationIDs...)
+
+	written := types.Endpoint{}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for _, inApp := range endpoint.Applications {
+		if inApp.UserID != "" && inApp.UserID != lb.UserID {
+			return nil, ErrUserMismatch
+		}
+
+		app := *inApp
+		app.UserID = lb.UserID
+
+		err = app.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		app.ID, err = random.HexString(idLength)
+		if err != nil {
+			return nil, err
+		}
+
+		app.CreatedAt = now
+		app.UpdatedAt = now
+
+		err = d.insertEndpointApplication(tx, &app)
+		if err != nil {
+			return nil, err
+		}
+
+		lb.ApplicationIDs = append(lb.ApplicationIDs, app.ID)
+		written.Applications = append(written.Applications, &app)
+	}
+
+	_, err = tx.Exec(insertEndpointLoadBalancerScript, lb.ID, newSQLNullString(lb.Name), newSQLNullString(lb.UserID),
+		newSQLNullInt32(int32(lb.RequestTimeout)), lb.Gigastake, lb.GigastakeRedirect, lb.CreatedAt, lb.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	sticky := lb.StickyOptions
+	if sticky.Duration != "" || len(sticky.StickyOrigins) > 0 || sticky.StickyMax != 0 {
+		_, err = tx.Exec(insertEndpointStickinessOptionsScript, lb.ID, newSQLNullString(sticky.Duration),
+			newSQLNullInt32(int32(sticky.StickyMax)), sticky.Stickiness, pq.StringArray(sticky.StickyOrigins))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, appID := range lb.ApplicationIDs {
+		_, err = tx.Exec(insertEndpointLbAppScript, lb.ID, appID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	written.LoadBalancer = lb
+
+	return &written, nil
+}
+
+// insertEndpointApplication inserts the application and its settings, settings left
+// empty are not inserted as done upstream
+func (d *Driver) insertEndpointApplication(tx execer, app *repository.Application) error {
+	_, err := tx.Exec(insertEndpointApplicationScript, app.ID, newSQLNullString(app.UserID), newSQLNullString(app.Name),
+		newSQLNullString(app.ContactEmail), newSQLNullString(app.Description), newSQLNullString(app.Owner),
+		newSQLNullString(app.URL), newSQLNullString(string(app.Status)), app.Dummy, app.CreatedAt, app.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(insertEndpointAppLimitScript, app.ID, newSQLNullString(string(app.Limit.PayPlan.Type)),
+		newSQLNullInt32(int32(app.Limit.CustomLimit)))
+	if err != nil {
+		return err
+	}
+
+	aat := app.GatewayAAT
+	if aat.Address != "" || aat.ClientPublicKey != "" || aat.PrivateKey != "" || aat.ApplicationPublicKey != "" ||
+		aat.ApplicationSignature != "" || aat.Version != "" {
+		privateKey, err := d.encrypt(aat.PrivateKey)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(insertEndpointGatewayAATScript, app.ID, newSQLNullString(aat.Address), newSQLNullString(aat.ClientPublicKey),
+			newSQLNullString(privateKey), newSQLNullString(aat.ApplicationPublicKey), newSQLNullString(aat.ApplicationSignature),
+			newSQLNullString(aat.Version))
+		if err != nil {
+			return err
+		}
+	}
+
+	settings := app.GatewaySettings
+
+	if settings.SecretKey != "" || len(settings.WhitelistContracts) > 0 || len(settings.WhitelistMethods) > 0 ||
+		len(settings.WhitelistOrigins) > 0 || len(settings.WhitelistUserAgents) > 0 || len(settings.WhitelistBlockchains) > 0 {
+		secretKey, err := d.encrypt(settings.SecretKey)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(insertEndpointGatewaySettingsScript, app.ID, newSQLNullString(secretKey), settings.SecretKeyRequired,
+			pq.StringArray(settings.WhitelistOrigins), pq.StringArray(settings.WhitelistUserAgents),
+			pq.StringArray(settings.WhitelistBlockchains))
+		if err != nil {
+			return err
+		}
+
+		err = replaceWhitelists(tx, app.ID, settings)
+		if err != nil {
+			return err
+		}
+	}
+
+	notifications := app.NotificationSettings
+
+	_, err = tx.Exec(insertEndpointNotificationSettingsScript, app.ID, notifications.SignedUp, notifications.Quarter,
+		notifications.Half, notifications.ThreeQuarters, notifications.Full)
+
+	return err
+}
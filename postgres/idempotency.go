@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+)
+
+const (
+	reserveIdempotencyKeyScript = `
+	INSERT into idempotency_keys (idempotency_key, caller, request_hash, created_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (idempotency_key, caller)
+	DO UPDATE SET request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = NULL, body = NULL, created_at = EXCLUDED.created_at
+	WHERE idempotency_keys.created_at < $5 OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < $6)
+	RETURNING idempotency_key`
+	selectIdempotencyKeyScript = `
+	SELECT request_hash, status_code, content_type, body
+	FROM idempotency_keys
+	WHERE idempotency_key = $1 AND caller = $2`
+	saveIdempotentResponseScript = `
+	UPDATE idempotency_keys
+	SET status_code = $1, content_type = $2, body = $3
+	WHERE idempotency_key = $4 AND caller = $5 AND status_code IS NULL`
+	releaseIdempotencyKeyScript      = `DELETE FROM idempotency_keys WHERE idempotency_key = $1 AND caller = $2 AND status_code IS NULL`
+	purgeExpiredIdempotencyKeyScript = `DELETE FROM idempotency_keys WHERE created_at < $1`
+)
+
+// ReserveIdempotencyKey saves the key for the caller and request unless it is already saved and not expired,
+// returning nil when reserved or what is saved for the key otherwise, keys whose response was not saved within
+// the lock timeout are reserved again as the request holding them is assumed to be lost
+func (d *Driver) ReserveIdempotencyKey(key, caller, requestHash string, ttl, lockTimeout time.Duration) (*types.IdempotentRequest, error) {
+	now := time.Now()
+
+	var reservedKey string
+
+	err := d.QueryRow(reserveIdempotencyKeyScript, key, caller, requestHash, now, now.Add(-ttl), now.Add(-lockTimeout)).Scan(&reservedKey)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	var statusCode sql.NullInt32
+	var contentType sql.NullString
+
+	saved := types.IdempotentRequest{}
+
+	err = d.QueryRow(selectIdempotencyKeyScript, key, caller).Scan(&saved.RequestHash, &statusCode, &contentType, &saved.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	saved.StatusCode = int(statusCode.Int32)
+	saved.ContentType = contentType.String
+
+	if d.encrypter != nil && len(saved.Body) > 0 {
+		body, err := d.encrypter.Decrypt(string(saved.Body))
+		if err != nil {
+			return nil, fmt.Errorf("response of idempotency key %s: %w", key, err)
+		}
+
+		saved.Body = []byte(body)
+	}
+
+	return &saved, nil
+}
+
+// SaveIdempotentResponse saves the response to replay for the reserved key, the body is encrypted as
+// it may carry secrets, only the first response saved for a reservation is kept
+func (d *Driver) SaveIdempotentResponse(key, caller string, response *types.IdempotentRequest) error {
+	body, err := d.encrypt(string(response.Body))
+	if err != nil {
+		return err
+	}
+
+	_, err = d.Exec(saveIdempotentResponseScript, response.StatusCode, newSQLNullString(response.ContentType), []byte(body), key, caller)
+
+	return err
+}
+
+// ReleaseIdempotencyKey deletes a reserved key whose response was not saved so the request can be retried
+func (d *Driver) ReleaseIdempotencyKey(key, caller string) error {
+	_, err := d.Exec(releaseIdempotencyKeyScript, key, caller)
+
+	return err
+}
+
+// PurgeExpiredIdempotencyKeys deletes the keys saved longer than the TTL ago, returning how many were deleted
+func (d *Driver) PurgeExpiredIdempotencyKeys(ttl time.Duration) (int64, error) {
+	result, err := d.Exec(purgeExpiredIdempotencyKeyScript, time.Now().Add(-ttl))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+	postgresdriver "github.com/pokt-foundation/portal-api-go/postgres-driver"
+	"github.com/pokt-foundation/portal-api-go/repository"
+)
+
+const updateLoadBalancerScript = `
+	UPDATE loadbalancers
+	SET name = COALESCE($1, name), updated_at = $2
+	WHERE lb_id = $3`
+
+// UpdateLoadBalancer updates the fields available in options in a single transaction, when ifUpdatedAt is given
+// the load balancer is updated only if it still has that updated at, otherwise types.ErrLoadBalancerChanged is
+// returned, returning the updated at the load balancer is left with
+func (d *Driver) UpdateLoadBalancer(id string, fieldsToUpdate *repository.UpdateLoadBalancer, ifUpdatedAt *time.Time) (time.Time, error) {
+	if id == "" {
+		return time.Time{}, postgresdriver.ErrMissingID
+	}
+
+	if fieldsToUpdate == nil {
+		return time.Time{}, postgresdriver.ErrNoFieldsToUpdate
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := updateTime()
+
+	claimed, err := claimUpdate(tx, claimLoadBalancerUpdateScript, id, ifUpdatedAt, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !claimed {
+		return time.Time{}, types.ErrLoadBalancerChanged
+	}
+
+	_, err = tx.Exec(updateLoadBalancerScript, newSQLNullString(fieldsToUpdate.Name), now, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if fieldsToUpdate.StickyOptions != nil {
+		err = d.upsertStickinessOptions(tx, id, fieldsToUpdate.StickyOptions)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return now, nil
+}
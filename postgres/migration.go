@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	_ "embed" // migration script
+)
+
+// migrationScript creates the tables and triggers PHD needs on top of the Portal schema
+//
+//go:embed migration.sql
+var migrationScript string
+
+// Migrate applies the PHD schema on the database in a single transaction, it can run again on a migrated database
+func (d *Driver) Migrate() error {
+	tx, err := d.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	_, err = tx.Exec(migrationScript)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,103 @@
+-- Tables and triggers PHD needs on top of the Portal schema, every statement can run again on a migrated database
+
+-- Applications Awaiting Grace Period, not notified as only PHD reads it
+CREATE TABLE IF NOT EXISTS application_removals (
+	application_id VARCHAR NOT NULL UNIQUE,
+	previous_status VARCHAR,
+	user_id VARCHAR,
+	removed_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (application_id),
+	CONSTRAINT fk_application
+      FOREIGN KEY(application_id) 
+	  	REFERENCES applications(application_id)
+);
+
+-- Applications Usage Thresholds Reached, not notified as only PHD reads it
+CREATE TABLE IF NOT EXISTS application_usage_states (
+	application_id VARCHAR NOT NULL UNIQUE,
+	thresholds_reached VARCHAR[] NOT NULL,
+	PRIMARY KEY (application_id),
+	CONSTRAINT fk_application
+      FOREIGN KEY(application_id) 
+	  	REFERENCES applications(application_id)
+);
+
+-- Removed Load Balancers, not notified as only PHD reads it
+CREATE TABLE IF NOT EXISTS load_balancer_removals (
+	lb_id VARCHAR NOT NULL UNIQUE,
+	user_id VARCHAR NOT NULL,
+	removed_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (lb_id),
+	CONSTRAINT fk_lb
+      FOREIGN KEY(lb_id) 
+	  	REFERENCES loadbalancers(lb_id)
+);
+
+-- Idempotency Keys, not notified as only PHD reads it
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+	idempotency_key VARCHAR NOT NULL,
+	caller VARCHAR NOT NULL,
+	request_hash VARCHAR NOT NULL,
+	status_code INT,
+	content_type VARCHAR,
+	body BYTEA,
+	created_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (idempotency_key, caller)
+);
+
+-- Whitelisted Contracts, one row per blockchain and address in the order they were whitelisted, they replace
+-- the JSON kept in gateway_settings which migrate_whitelists moves here, not notified as gateway_settings is
+-- updated along every change
+CREATE TABLE IF NOT EXISTS whitelist_contracts (
+	id INT GENERATED ALWAYS AS IDENTITY,
+	application_id VARCHAR NOT NULL,
+	blockchain_id VARCHAR NOT NULL,
+	address VARCHAR NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE (application_id, blockchain_id, address),
+	CONSTRAINT fk_application
+      FOREIGN KEY(application_id) 
+	  	REFERENCES applications(application_id)
+);
+
+-- Whitelisted Methods, one row per blockchain and method in the order they were whitelisted, they replace
+-- the JSON kept in gateway_settings which migrate_whitelists moves here, not notified as gateway_settings is
+-- updated along every change
+CREATE TABLE IF NOT EXISTS whitelist_methods (
+	id INT GENERATED ALWAYS AS IDENTITY,
+	application_id VARCHAR NOT NULL,
+	blockchain_id VARCHAR NOT NULL,
+	method VARCHAR NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE (application_id, blockchain_id, method),
+	CONSTRAINT fk_application
+      FOREIGN KEY(application_id) 
+	  	REFERENCES applications(application_id)
+);
+
+-- Applications removed before their removals were recorded get a full grace period from the migration,
+-- their previous status is unknown so restoring them puts them back in service
+INSERT INTO application_removals (application_id, previous_status, user_id, removed_at)
+SELECT application_id, NULL, user_id, NOW() FROM applications WHERE status = 'AWAITING_GRACE_PERIOD'
+ON CONFLICT (application_id) DO NOTHING;
+
+-- Purges, AAT rotations and full blockchain updates must reach the cache of every instance
+DROP TRIGGER IF EXISTS loadbalancer_notify_event ON loadbalancers;
+CREATE TRIGGER loadbalancer_notify_event
+AFTER INSERT OR UPDATE OR DELETE ON loadbalancers
+    FOR EACH ROW EXECUTE PROCEDURE notify_event();
+
+DROP TRIGGER IF EXISTS application_notify_event ON applications;
+CREATE TRIGGER application_notify_event
+AFTER INSERT OR UPDATE OR DELETE ON applications
+    FOR EACH ROW EXECUTE PROCEDURE notify_event();
+
+DROP TRIGGER IF EXISTS gateway_aat_notify_event ON gateway_aat;
+CREATE TRIGGER gateway_aat_notify_event
+AFTER INSERT OR UPDATE ON gateway_aat
+    FOR EACH ROW EXECUTE PROCEDURE notify_event();
+
+DROP TRIGGER IF EXISTS sync_check_options_notify_event ON sync_check_options;
+CREATE TRIGGER sync_check_options_notify_event
+AFTER INSERT OR UPDATE ON sync_check_options
+    FOR EACH ROW EXECUTE PROCEDURE notify_event();
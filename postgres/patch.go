@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pokt-foundation/pocket-http-db/types"
+	postgresdriver "github.com/pokt-foundation/portal-api-go/postgres-driver"
+	"github.com/pokt-foundation/portal-api-go/repository"
+)
+
+const (
+	patchApplicationScript = `
+	UPDATE applications
+	SET name = $1, status = $2, first_date_surpassed = $3, updated_at = $4
+	WHERE application_id = $5`
+	upsertAppLimitScript = `
+	INSERT into app_limits (application_id, pay_plan, custom_limit)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (application_id)
+	DO UPDATE SET pay_plan = EXCLUDED.pay_plan, custom_limit = EXCLUDED.custom_limit`
+	upsertGatewaySettingsScript = `
+	INSERT into gateway_settings (application_id, secret_key, secret_key_required, whitelist_origins, whitelist_user_agents, whitelist_blockchains)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (application_id)
+	DO UPDATE SET secret_key = EXCLUDED.secret_key, secret_key_required = EXCLUDED.secret_key_required,
+	whitelist_contracts = NULL, whitelist_methods = NULL,
+	whitelist_origins = EXCLUDED.whitelist_origins, whitelist_user_agents = EXCLUDED.whitelist_user_agents,
+	whitelist_blockchains = EXCLUDED.whitelist_blockchains`
+	upsertNotificationSettingsScript = `
+	INSERT into notification_settings (application_id, signed_up, on_quarter, on_half, on_three_quarters, on_full)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (application_id)
+	DO UPDATE SET signed_up = EXCLUDED.signed_up, on_quarter = EXCLUDED.on_quarter, on_half = EXCLUDED.on_half,
+	on_three_quarters = EXCLUDED.on_three_quarters, on_full = EXCLUDED.on_full`
+	patchLoadBalancerScript = `
+	UPDATE loadbalancers
+	SET name = $1, updated_at = $2
+	WHERE lb_id = $3`
+	upsertStickinessOptionsScript = `
+	INSERT into stickiness_options (lb_id, duration, sticky_max, stickiness, origins)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (lb_id)
+	DO UPDATE SET duration = EXCLUDED.duration, sticky_max = EXCLUDED.sticky_max, stickiness = EXCLUDED.stickiness,
+	origins = EXCLUDED.origins`
+)
+
+// PatchApplication saves the name, status, first date surpassed, limit, gateway settings and notification
+// settings of the application as they are in a single transaction, unlike UpdateApplication empty values
+// clear the stored ones, when ifUpdatedAt is given the application is saved only if it still has that
+// updated at, otherwise types.ErrApplicationChanged is returned, returning the updated at it is left with
+func (d *Driver) PatchApplication(app *repository.Application, ifUpdatedAt *time.Time) (time.Time, error) {
+	if app.ID == "" {
+		return time.Time{}, postgresdriver.ErrMissingID
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := updateTime()
+
+	claimed, err := claimUpdate(tx, claimApplicationUpdateScript, app.ID, ifUpdatedAt, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !claimed {
+		return time.Time{}, types.ErrApplicationChanged
+	}
+
+	err = d.patchApplication(tx, app, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return now, nil
+}
+
+// PatchApplications saves the applications as PatchApplication does in a single transaction, each application is
+// saved only if its updated at still is the given one, otherwise nothing is saved and types.ErrApplicationChanged is returned
+func (d *Driver) PatchApplications(apps []*repository.Application) error {
+	for _, app := range apps {
+		if app.ID == "" {
+			return postgresdriver.ErrMissingID
+		}
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := updateTime()
+
+	for _, app := range apps {
+		claimed, err := claimUpdate(tx, claimApplicationUpdateScript, app.ID, &app.UpdatedAt, now)
+		if err != nil {
+			return err
+		}
+
+		if !claimed {
+			return fmt.Errorf("%w: %s", types.ErrApplicationChanged, app.ID)
+		}
+
+		err = d.patchApplication(tx, app, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (d *Driver) patchApplication(tx execer, app *repository.Application, updatedAt time.Time) error {
+	_, err := tx.Exec(patchApplicationScript, newSQLNullString(app.Name), newSQLNullString(string(app.Status)),
+		newSQLNullTime(app.FirstDateSurpassed), updatedAt, app.ID)
+	if err != nil {
+		return err
+	}
+
+	return d.upsertApplicationSettings(tx, app.ID, &app.Limit, &app.GatewaySettings, &app.NotificationSettings)
+}
+
+// upsertApplicationSettings saves the limit, gateway settings and notification settings of the application
+// as they are, the nil ones are left untouched
+func (d *Driver) upsertApplicationSettings(tx execer, id string, limit *repository.AppLimit,
+	settings *repository.GatewaySettings, notifications *repository.NotificationSettings) error {
+	if limit != nil {
+		_, err := tx.Exec(upsertAppLimitScript, id, newSQLNullString(string(limit.PayPlan.Type)),
+			newSQLNullInt32(int32(limit.CustomLimit)))
+		if err != nil {
+			return err
+		}
+	}
+
+	if settings != nil {
+		secretKey, err := d.encrypt(settings.SecretKey)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(upsertGatewaySettingsScript, id, newSQLNullString(secretKey), settings.SecretKeyRequired,
+			pq.StringArray(settings.WhitelistOrigins), pq.StringArray(settings.WhitelistUserAgents),
+			pq.StringArray(settings.WhitelistBlockchains))
+		if err != nil {
+			return err
+		}
+
+		err = replaceWhitelists(tx, id, *settings)
+		if err != nil {
+			return err
+		}
+	}
+
+	if notifications != nil {
+		_, err := tx.Exec(upsertNotificationSettingsScript, id, notifications.SignedUp, notifications.Quarter,
+			notifications.Half, notifications.ThreeQuarters, notifications.Full)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PatchLoadBalancer saves the name and stickiness options of the load balancer as they are in a single
+// transaction, unlike UpdateLoadBalancer empty values clear the stored ones, when ifUpdatedAt is given the load
+// balancer is saved only if it still has that updated at, otherwise types.ErrLoadBalancerChanged is returned,
+// returning the updated at it is left with
+func (d *Driver) PatchLoadBalancer(lb *repository.LoadBalancer, ifUpdatedAt *time.Time) (time.Time, error) {
+	if lb.ID == "" {
+		return time.Time{}, postgresdriver.ErrMissingID
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := updateTime()
+
+	claimed, err := claimUpdate(tx, claimLoadBalancerUpdateScript, lb.ID, ifUpdatedAt, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !claimed {
+		return time.Time{}, types.ErrLoadBalancerChanged
+	}
+
+	_, err = tx.Exec(patchLoadBalancerScript, newSQLNullString(lb.Name), now, lb.ID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = d.upsertStickinessOptions(tx, lb.ID, &lb.StickyOptions)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return now, nil
+}
+
+func (d *Driver) upsertStickinessOptions(tx execer, id string, sticky *repository.StickyOptions) error {
+	_, err := tx.Exec(upsertStickinessOptionsScript, id, newSQLNullString(sticky.Duration),
+		newSQLNullInt32(int32(sticky.StickyMax)), sticky.Stickiness, pq.StringArray(sticky.StickyOrigins))
+
+	return err
+}
@@ -0,0 +1,88 @@
+// Package postgres extends the portal-api-go postgres driver with the queries
+// PHD needs that are not available upstream
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pokt-foundation/pocket-http-db/encryption"
+	postgresdriver "github.com/pokt-foundation/portal-api-go/postgres-driver"
+	"github.com/pokt-foundation/portal-api-go/repository"
+	"github.com/sirupsen/logrus"
+)
+
+var errNoEncrypter = errors.New("encryption is not configured")
+
+// Driver struct handler for PostgresDB related functions, embeds the portal-api-go
+// driver so all of its reads and writes are available as well
+type Driver struct {
+	*postgresdriver.PostgresDriver
+	encrypter *encryption.Encrypter
+	log       *logrus.Logger
+
+	notificationsOnce sync.Once
+	notifications     <-chan *repository.Notification
+}
+
+// NewDriver returns Driver instance from a portal-api-go postgres driver, secrets are
+// encrypted at rest with the encrypter unless it is nil
+func NewDriver(driver *postgresdriver.PostgresDriver, encrypter *encryption.Encrypter, logger *logrus.Logger) *Driver {
+	return &Driver{
+		PostgresDriver: driver,
+		encrypter:      encrypter,
+		log:            logger,
+	}
+}
+
+func (d *Driver) logError(err error) {
+	fields := logrus.Fields{
+		"err": err.Error(),
+	}
+
+	d.log.WithFields(fields).Error(err)
+}
+
+// encrypt returns the secret encrypted, as it is when encryption is not configured
+func (d *Driver) encrypt(secret string) (string, error) {
+	if d.encrypter == nil {
+		return secret, nil
+	}
+
+	return d.encrypter.Encrypt(secret)
+}
+
+func newSQLNullString(value string) sql.NullString {
+	if value == "" {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{
+		String: value,
+		Valid:  true,
+	}
+}
+
+func newSQLNullInt32(value int32) sql.NullInt32 {
+	if value == 0 {
+		return sql.NullInt32{}
+	}
+
+	return sql.NullInt32{
+		Int32: value,
+		Valid: true,
+	}
+}
+
+func newSQLNullTime(value time.Time) sql.NullTime {
+	if value.IsZero() {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{
+		Time:  value,
+		Valid: true,
+	}
+}
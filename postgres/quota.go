@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+	"github.com/pokt-foundation/portal-api-go/repository"
+)
+
+const (
+	tryLockUserQuotaScript  = `SELECT pg_try_advisory_lock(hashtext($1))`
+	unlockUserQuotaScript   = `SELECT pg_advisory_unlock(hashtext($1))`
+	countApplicationsScript = `
+	SELECT COALESCE(l.pay_plan, ''), COUNT(*)
+	FROM applications AS a
+	LEFT JOIN app_limits AS l ON l.application_id = a.application_id
+	WHERE a.user_id = $1 AND a.status IS DISTINCT FROM $2
+	GROUP BY 1`
+	countLoadBalancersScript = `SELECT COUNT(*) FROM loadbalancers WHERE user_id = $1`
+)
+
+// userQuotaLockRetryInterval is how long to wait before trying again to take the lock held by another request
+const userQuotaLockRetryInterval = 25 * time.Millisecond
+
+// LockUserQuota takes a lock on the quotas of the user shared by every instance, returning the function
+// that releases it, the lock is held by its own connection so it spans the writes done meanwhile, waiting
+// for the lock to be released by another request is given up once the context is done
+func (d *Driver) LockUserQuota(ctx context.Context, userID string) (func(), error) {
+	if userID == "" {
+		return nil, ErrMissingUserID
+	}
+
+	conn, err := d.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var locked bool
+
+		err = conn.QueryRowContext(ctx, tryLockUserQuotaScript, userID).Scan(&locked)
+		if err != nil {
+			conn.Close() //nolint:errcheck
+
+			// a query canceled as the context is done fails with the error of the driver instead
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			return nil, err
+		}
+
+		if locked {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			conn.Close() //nolint:errcheck
+			return nil, ctx.Err()
+		case <-time.After(userQuotaLockRetryInterval):
+		}
+	}
+
+	return func() {
+		// the context of the request may be done by the time the lock is released
+		_, err := conn.ExecContext(context.Background(), unlockUserQuotaScript, userID)
+		if err != nil {
+			d.logError(fmt.Errorf("unlock quotas of user %s failed: %w", userID, err))
+			discardConn(conn)
+		}
+
+		conn.Close() //nolint:errcheck
+	}, nil
+}
+
+// discardConn closes the connection instead of returning it to the pool, so the
+// session ends along with the locks it may still hold
+func discardConn(conn *sql.Conn) {
+	conn.Raw(func(any) error { //nolint:errcheck
+		return driver.ErrBadConn
+	})
+}
+
+// ReadUserEntityCounts returns how many applications of each pay plan and load balancers the user owns,
+// applications awaiting their grace period do not count as they are about to be purged
+func (d *Driver) ReadUserEntityCounts(userID string) (*types.UserEntityCounts, error) {
+	if userID == "" {
+		return nil, ErrMissingUserID
+	}
+
+	counts := &types.UserEntityCounts{
+		Applications: make(map[repository.PayPlanType]int),
+	}
+
+	rows, err := d.Query(countApplicationsScript, userID, string(repository.AwaitingGracePeriod))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payPlan string
+		var count int
+
+		err = rows.Scan(&payPlan, &count)
+		if err != nil {
+			return nil, err
+		}
+
+		counts.Applications[repository.PayPlanType(payPlan)] = count
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.QueryRow(countLoadBalancersScript, userID).Scan(&counts.LoadBalancers)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
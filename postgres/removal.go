@@ -0,0 +1,385 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+	postgresdriver "github.com/pokt-foundation/portal-api-go/postgres-driver"
+	"github.com/pokt-foundation/portal-api-go/repository"
+)
+
+const (
+	deleteStaleApplicationRemovalScript = `
+	DELETE FROM application_removals AS r
+	USING applications AS a
+	WHERE r.application_id = $1 AND a.application_id = r.application_id AND a.status IS DISTINCT FROM $2`
+	insertApplicationRemovalScript = `
+	INSERT into application_removals (application_id, previous_status, user_id, removed_at)
+	SELECT application_id, status, user_id, $1 FROM applications WHERE application_id = $2
+	ON CONFLICT (application_id) DO NOTHING`
+	removeApplicationScript = `
+	UPDATE applications
+	SET status = $1, updated_at = $2
+	WHERE application_id = $3`
+	selectApplicationRemovalScript = `
+	SELECT r.previous_status, r.user_id, r.removed_at
+	FROM application_removals AS r
+	INNER JOIN applications AS a ON a.application_id = r.application_id
+	WHERE r.application_id = $1 AND a.status = $2
+	FOR UPDATE OF r`
+	restoreApplicationScript = `
+	UPDATE applications
+	SET status = $1, user_id = COALESCE($2, user_id), updated_at = $3
+	WHERE application_id = $4`
+	deleteApplicationRemovalScript         = `DELETE FROM application_removals WHERE application_id = $1`
+	selectExpiredApplicationRemovalsScript = `
+	SELECT r.application_id
+	FROM application_removals AS r
+	INNER JOIN applications AS a ON a.application_id = r.application_id
+	WHERE r.removed_at < $1 AND a.status = $2
+	FOR UPDATE OF r SKIP LOCKED`
+	insertLoadBalancerRemovalScript = `
+	INSERT into load_balancer_removals (lb_id, user_id, removed_at)
+	SELECT lb_id, COALESCE(user_id, ''), $1 FROM loadbalancers WHERE lb_id = $2
+	ON CONFLICT (lb_id) DO NOTHING`
+	removeLoadBalancerScript = `
+	UPDATE loadbalancers
+	SET user_id = '', updated_at = $1
+	WHERE lb_id = $2`
+	selectLoadBalancerRemovalScript = `
+	SELECT r.user_id, r.removed_at
+	FROM load_balancer_removals AS r
+	INNER JOIN loadbalancers AS l ON l.lb_id = r.lb_id
+	WHERE r.lb_id = $1 AND l.user_id = ''
+	FOR UPDATE OF r`
+	restoreLoadBalancerScript = `
+	UPDATE loadbalancers
+	SET user_id = $1, updated_at = $2
+	WHERE lb_id = $3`
+	selectLoadBalancerRemovalsScript        = `SELECT lb_id, user_id, removed_at FROM load_balancer_removals`
+	selectLoadBalancerRemovalByIDScript     = `SELECT lb_id, user_id, removed_at FROM load_balancer_removals WHERE lb_id = $1`
+	deleteLoadBalancerRemovalScript         = `DELETE FROM load_balancer_removals WHERE lb_id = $1`
+	selectExpiredLoadBalancerRemovalsScript = `
+	SELECT r.lb_id
+	FROM load_balancer_removals AS r
+	INNER JOIN loadbalancers AS l ON l.lb_id = r.lb_id
+	WHERE r.removed_at < $1 AND l.user_id = ''
+	FOR UPDATE OF r SKIP LOCKED`
+)
+
+// purgeApplicationScripts delete an application and every row referencing it, children first
+var purgeApplicationScripts = []string{
+	`DELETE FROM lb_apps WHERE app_id = $1`,
+	`DELETE FROM app_limits WHERE application_id = $1`,
+	`DELETE FROM gateway_aat WHERE application_id = $1`,
+	`DELETE FROM gateway_settings WHERE application_id = $1`,
+	`DELETE FROM whitelist_contracts WHERE application_id = $1`,
+	`DELETE FROM whitelist_methods WHERE application_id = $1`,
+	`DELETE FROM notification_settings WHERE application_id = $1`,
+	`DELETE FROM application_removals WHERE application_id = $1`,
+	`DELETE FROM application_usage_states WHERE application_id = $1`,
+	`DELETE FROM applications WHERE application_id = $1`,
+}
+
+// purgeLoadBalancerScripts delete a load balancer and every row referencing it, children first
+var purgeLoadBalancerScripts = []string{
+	`DELETE FROM lb_apps WHERE lb_id = $1`,
+	`DELETE FROM stickiness_options WHERE lb_id = $1`,
+	`DELETE FROM load_balancer_removals WHERE lb_id = $1`,
+	`DELETE FROM loadbalancers WHERE lb_id = $1`,
+}
+
+var (
+	ErrApplicationNotRemoved  = errors.New("application is not awaiting grace period")
+	ErrLoadBalancerNotRemoved = errors.New("load balancer is not removed")
+)
+
+type dbApplicationRemoval struct {
+	PreviousStatus sql.NullString
+	UserID         sql.NullString
+	RemovedAt      time.Time
+}
+
+// RemoveApplication sets the application to await its grace period, recording the status and owner it had
+// so it can be restored until the grace period expires, when ifUpdatedAt is given the application is removed
+// only if it still has that updated at, otherwise types.ErrApplicationChanged is returned, returning the
+// updated at the application is left with
+func (d *Driver) RemoveApplication(id string, ifUpdatedAt *time.Time) (time.Time, error) {
+	if id == "" {
+		return time.Time{}, postgresdriver.ErrMissingID
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := updateTime()
+	awaitingGracePeriod := string(repository.AwaitingGracePeriod)
+
+	claimed, err := claimUpdate(tx, claimApplicationUpdateScript, id, ifUpdatedAt, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !claimed {
+		return time.Time{}, types.ErrApplicationChanged
+	}
+
+	// a record left by an earlier removal is stale once the application left the grace period
+	_, err = tx.Exec(deleteStaleApplicationRemovalScript, id, awaitingGracePeriod)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	_, err = tx.Exec(insertApplicationRemovalScript, now, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	_, err = tx.Exec(removeApplicationScript, awaitingGracePeriod, now, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return now, nil
+}
+
+// RestoreApplication reverts the removal of an application awaiting its grace period,
+// returning the removal that was undone
+func (d *Driver) RestoreApplication(id string) (*types.ApplicationRemoval, error) {
+	if id == "" {
+		return nil, postgresdriver.ErrMissingID
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var removal dbApplicationRemoval
+
+	err = tx.QueryRow(selectApplicationRemovalScript, id, string(repository.AwaitingGracePeriod)).
+		Scan(&removal.PreviousStatus, &removal.UserID, &removal.RemovedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrApplicationNotRemoved
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// removals backfilled by the migration did not record the status the application had
+	if !removal.PreviousStatus.Valid {
+		removal.PreviousStatus = newSQLNullString(string(repository.InService))
+	}
+
+	_, err = tx.Exec(restoreApplicationScript, removal.PreviousStatus, removal.UserID, time.Now(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(deleteApplicationRemovalScript, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ApplicationRemoval{
+		ApplicationID:  id,
+		PreviousStatus: repository.AppStatus(removal.PreviousStatus.String),
+		UserID:         removal.UserID.String,
+		RemovedAt:      removal.RemovedAt,
+	}, nil
+}
+
+// PurgeExpiredApplications permanently deletes the applications removed longer than the grace
+// period ago, returning their IDs, rows locked by another instance purging are skipped
+func (d *Driver) PurgeExpiredApplications(gracePeriod time.Duration) ([]string, error) {
+	tx, err := d.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var ids []string
+
+	err = tx.Select(&ids, selectExpiredApplicationRemovalsScript, time.Now().Add(-gracePeriod), string(repository.AwaitingGracePeriod))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		for _, script := range purgeApplicationScripts {
+			_, err = tx.Exec(script, id)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ids, tx.Commit()
+}
+
+// RemoveLoadBalancer takes the load balancer away from its owner, recording the owner so the load balancer
+// can be restored until it is purged, when ifUpdatedAt is given the load balancer is removed only if it still
+// has that updated at, otherwise types.ErrLoadBalancerChanged is returned, returning the updated at the load
+// balancer is left with
+func (d *Driver) RemoveLoadBalancer(id string, ifUpdatedAt *time.Time) (time.Time, error) {
+	if id == "" {
+		return time.Time{}, postgresdriver.ErrMissingID
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := updateTime()
+
+	claimed, err := claimUpdate(tx, claimLoadBalancerUpdateScript, id, ifUpdatedAt, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !claimed {
+		return time.Time{}, types.ErrLoadBalancerChanged
+	}
+
+	_, err = tx.Exec(insertLoadBalancerRemovalScript, now, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	_, err = tx.Exec(removeLoadBalancerScript, now, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return now, nil
+}
+
+// ReadLoadBalancerRemovals returns the removals of every load balancer not restored or purged yet
+func (d *Driver) ReadLoadBalancerRemovals() ([]*types.LoadBalancerRemoval, error) {
+	rows, err := d.Query(selectLoadBalancerRemovalsScript)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var removals []*types.LoadBalancerRemoval
+
+	for rows.Next() {
+		var removal types.LoadBalancerRemoval
+
+		err = rows.Scan(&removal.LoadBalancerID, &removal.UserID, &removal.RemovedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		removals = append(removals, &removal)
+	}
+
+	return removals, rows.Err()
+}
+
+// ReadLoadBalancerRemoval returns the removal of the load balancer, nil when it is not removed
+func (d *Driver) ReadLoadBalancerRemoval(id string) (*types.LoadBalancerRemoval, error) {
+	var removal types.LoadBalancerRemoval
+
+	err := d.QueryRow(selectLoadBalancerRemovalByIDScript, id).Scan(&removal.LoadBalancerID, &removal.UserID, &removal.RemovedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &removal, nil
+}
+
+// RestoreLoadBalancer gives a removed load balancer back to its owner, returning the removal that was undone
+func (d *Driver) RestoreLoadBalancer(id string) (*types.LoadBalancerRemoval, error) {
+	if id == "" {
+		return nil, postgresdriver.ErrMissingID
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	removal := types.LoadBalancerRemoval{LoadBalancerID: id}
+
+	err = tx.QueryRow(selectLoadBalancerRemovalScript, id).Scan(&removal.UserID, &removal.RemovedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrLoadBalancerNotRemoved
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(restoreLoadBalancerScript, removal.UserID, time.Now(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(deleteLoadBalancerRemovalScript, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &removal, nil
+}
+
+// PurgeExpiredLoadBalancers permanently deletes the load balancers removed longer than the grace
+// period ago along with their lb_apps and stickiness_options, returning their IDs
+func (d *Driver) PurgeExpiredLoadBalancers(gracePeriod time.Duration) ([]string, error) {
+	tx, err := d.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var ids []string
+
+	err = tx.Select(&ids, selectExpiredLoadBalancerRemovalsScript, time.Now().Add(-gracePeriod))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		for _, script := range purgeLoadBalancerScripts {
+			_, err = tx.Exec(script, id)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ids, tx.Commit()
+}
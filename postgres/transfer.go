@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+	postgresdriver "github.com/pokt-foundation/portal-api-go/postgres-driver"
+)
+
+const (
+	transferUserApplicationsScript = `
+	UPDATE applications
+	SET user_id = $1, updated_at = $2
+	WHERE user_id = $3
+	RETURNING application_id`
+	transferUserLoadBalancersScript = `
+	UPDATE loadbalancers
+	SET user_id = $1, updated_at = $2
+	WHERE user_id = $3
+	RETURNING lb_id`
+	transferUserApplicationRemovalsScript  = `UPDATE application_removals SET user_id = $1 WHERE user_id = $2`
+	transferUserLoadBalancerRemovalsScript = `UPDATE load_balancer_removals SET user_id = $1 WHERE user_id = $2`
+	transferApplicationScript              = `
+	UPDATE applications
+	SET user_id = $1, updated_at = $2
+	WHERE application_id = $3`
+	transferApplicationRemovalScript = `UPDATE application_removals SET user_id = $1 WHERE application_id = $2`
+	transferLoadBalancerScript       = `
+	UPDATE loadbalancers
+	SET user_id = $1, updated_at = $2
+	WHERE lb_id = $3 AND user_id <> ''`
+)
+
+var (
+	ErrMissingUserID = errors.New("missing user id")
+	ErrSameUser      = errors.New("entities already owned by the user")
+)
+
+// TransferUserEntities moves the ownership of every application and load balancer of a user to
+// another one in a single transaction, including the removed ones so restoring gives them to the new owner
+func (d *Driver) TransferUserEntities(fromUserID, toUserID string) (*types.TransferredEntities, error) {
+	if fromUserID == "" || toUserID == "" {
+		return nil, ErrMissingUserID
+	}
+
+	if fromUserID == toUserID {
+		return nil, ErrSameUser
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := time.Now()
+
+	transferred := types.TransferredEntities{
+		ApplicationIDs:  []string{},
+		LoadBalancerIDs: []string{},
+	}
+
+	err = tx.Select(&transferred.ApplicationIDs, transferUserApplicationsScript, toUserID, now, fromUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Select(&transferred.LoadBalancerIDs, transferUserLoadBalancersScript, toUserID, now, fromUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, script := range []string{transferUserApplicationRemovalsScript, transferUserLoadBalancerRemovalsScript} {
+		_, err = tx.Exec(script, toUserID, fromUserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &transferred, nil
+}
+
+// TransferApplication gives the ownership of the application to the user
+func (d *Driver) TransferApplication(id, userID string) error {
+	if id == "" {
+		return postgresdriver.ErrMissingID
+	}
+
+	if userID == "" {
+		return ErrMissingUserID
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	_, err = tx.Exec(transferApplicationScript, userID, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(transferApplicationRemovalScript, userID, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// TransferLoadBalancer gives the ownership of the load balancer to the user, removed load balancers are left untouched
+func (d *Driver) TransferLoadBalancer(id, userID string) error {
+	if id == "" {
+		return postgresdriver.ErrMissingID
+	}
+
+	if userID == "" {
+		return ErrMissingUserID
+	}
+
+	_, err := d.Exec(transferLoadBalancerScript, userID, time.Now(), id)
+
+	return err
+}
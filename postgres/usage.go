@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pokt-foundation/pocket-http-db/types"
+	postgresdriver "github.com/pokt-foundation/portal-api-go/postgres-driver"
+)
+
+const (
+	selectUsageStateScript = `
+	SELECT a.first_date_surpassed, COALESCE(u.thresholds_reached, '{}')
+	FROM applications AS a
+	LEFT JOIN application_usage_states AS u ON u.application_id = a.application_id
+	WHERE a.application_id = $1`
+	selectFirstDateSurpassedScript = `
+	SELECT first_date_surpassed
+	FROM applications
+	WHERE application_id = $1
+	FOR UPDATE`
+	updateFirstDateSurpassedScript = `
+	UPDATE applications
+	SET first_date_surpassed = $1, updated_at = $2
+	WHERE application_id = $3
+	RETURNING first_date_surpassed`
+	selectThresholdsReachedScript = `SELECT thresholds_reached FROM application_usage_states WHERE application_id = $1`
+	addThresholdsReachedScript    = `
+	INSERT into application_usage_states (application_id, thresholds_reached)
+	VALUES ($1, ARRAY(SELECT DISTINCT unnest($2::VARCHAR[]) ORDER BY 1))
+	ON CONFLICT (application_id)
+	DO UPDATE SET thresholds_reached = ARRAY(
+		SELECT DISTINCT unnest(application_usage_states.thresholds_reached || EXCLUDED.thresholds_reached) ORDER BY 1)
+	RETURNING thresholds_reached`
+	resetThresholdsReachedScript      = `DELETE FROM application_usage_states WHERE application_id = $1`
+	usageStateSavepointScript         = `SAVEPOINT usage_state`
+	rollbackUsageStateSavepointScript = `ROLLBACK TO SAVEPOINT usage_state`
+	releaseUsageStateSavepointScript  = `RELEASE SAVEPOINT usage_state`
+)
+
+// ReadUsageState returns the usage state of the application
+func (d *Driver) ReadUsageState(id string) (*types.UsageState, error) {
+	if id == "" {
+		return nil, postgresdriver.ErrMissingID
+	}
+
+	var firstDateSurpassed sql.NullTime
+	var thresholds pq.StringArray
+
+	err := d.QueryRow(selectUsageStateScript, id).Scan(&firstDateSurpassed, &thresholds)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrApplicationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.UsageState{
+		ApplicationID:      id,
+		FirstDateSurpassed: firstDateSurpassed.Time,
+		ThresholdsReached:  toUsageThresholds(thresholds),
+	}, nil
+}
+
+// usageStateTx is the part of a transaction a usage state update needs
+type usageStateTx interface {
+	execer
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// UpdateUsageStates applies the usage state updates in a single transaction, returning the usage state each one
+// leaves and the error each one failed with in the same order, every update runs in its own savepoint so a failed
+// one is rolled back on its own and the rest of the batch is still saved, updates of applications not found fail
+// with ErrApplicationNotFound, the error returned alone is set when the batch as a whole could not be saved
+func (d *Driver) UpdateUsageStates(updates []*types.UsageStateUpdate) ([]*types.UsageState, []error, error) {
+	for _, update := range updates {
+		err := update.Validate()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := updateTime()
+	states := make([]*types.UsageState, len(updates))
+	errs := make([]error, len(updates))
+
+	for i, update := range updates {
+		_, err = tx.Exec(usageStateSavepointScript)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		states[i], errs[i] = updateUsageState(tx, update, now)
+
+		if errs[i] != nil {
+			_, err = tx.Exec(rollbackUsageStateSavepointScript)
+		} else {
+			_, err = tx.Exec(releaseUsageStateSavepointScript)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return states, errs, nil
+}
+
+// updateUsageState applies a single usage state update in the transaction
+func updateUsageState(tx usageStateTx, update *types.UsageStateUpdate, now time.Time) (*types.UsageState, error) {
+	var firstDateSurpassed sql.NullTime
+
+	var err error
+
+	// the application row is locked either way so updates of the same application are applied one after the other
+	if update.Reset || !update.FirstDateSurpassed.IsZero() {
+		err = tx.QueryRow(updateFirstDateSurpassedScript, newSQLNullTime(update.FirstDateSurpassed), now,
+			update.ApplicationID).Scan(&firstDateSurpassed)
+	} else {
+		err = tx.QueryRow(selectFirstDateSurpassedScript, update.ApplicationID).Scan(&firstDateSurpassed)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrApplicationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var thresholds pq.StringArray
+
+	switch {
+	case update.Reset:
+		_, err = tx.Exec(resetThresholdsReachedScript, update.ApplicationID)
+	case len(update.ThresholdsReached) > 0:
+		values := make(pq.StringArray, 0, len(update.ThresholdsReached))
+		for _, threshold := range update.ThresholdsReached {
+			values = append(values, string(threshold))
+		}
+
+		err = tx.QueryRow(addThresholdsReachedScript, update.ApplicationID, values).Scan(&thresholds)
+	default:
+		err = tx.QueryRow(selectThresholdsReachedScript, update.ApplicationID).Scan(&thresholds)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.UsageState{
+		ApplicationID:      update.ApplicationID,
+		FirstDateSurpassed: firstDateSurpassed.Time,
+		ThresholdsReached:  toUsageThresholds(thresholds),
+	}, nil
+}
+
+func toUsageThresholds(values []string) []types.UsageThreshold {
+	thresholds := make([]types.UsageThreshold, 0, len(values))
+
+	for _, value := range values {
+		thresholds = append(thresholds, types.UsageThreshold(value))
+	}
+
+	return thresholds
+}
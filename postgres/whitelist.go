@@ -0,0 +1,393 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pokt-foundation/pocket-http-db/types"
+	postgresdriver "github.com/pokt-foundation/portal-api-go/postgres-driver"
+	"github.com/pokt-foundation/portal-api-go/repository"
+)
+
+const (
+	touchApplicationScript = `
+	UPDATE applications
+	SET updated_at = $1
+	WHERE application_id = $2`
+	selectWhitelistsScript = `
+	SELECT whitelist_contracts, whitelist_methods, whitelist_origins, whitelist_user_agents, whitelist_blockchains
+	FROM gateway_settings
+	WHERE application_id = $1`
+	upsertWhitelistsScript = `
+	INSERT into gateway_settings (application_id, whitelist_origins, whitelist_user_agents, whitelist_blockchains)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (application_id)
+	DO UPDATE SET whitelist_contracts = NULL, whitelist_methods = NULL,
+	whitelist_origins = EXCLUDED.whitelist_origins, whitelist_user_agents = EXCLUDED.whitelist_user_agents,
+	whitelist_blockchains = EXCLUDED.whitelist_blockchains`
+	selectContractsAndMethodsScript = `
+	SELECT application_id, whitelist_contracts, whitelist_methods
+	FROM gateway_settings
+	WHERE whitelist_contracts IS NOT NULL OR whitelist_methods IS NOT NULL
+	FOR UPDATE`
+	clearContractsAndMethodsScript = `
+	UPDATE gateway_settings
+	SET whitelist_contracts = NULL, whitelist_methods = NULL
+	WHERE application_id = $1`
+	selectWhitelistContractsScript = `
+	SELECT application_id, blockchain_id, address
+	FROM whitelist_contracts
+	WHERE $1 = '' OR application_id = $1
+	ORDER BY id`
+	selectWhitelistMethodsScript = `
+	SELECT application_id, blockchain_id, method
+	FROM whitelist_methods
+	WHERE $1 = '' OR application_id = $1
+	ORDER BY id`
+	deleteWhitelistContractsScript = `DELETE FROM whitelist_contracts WHERE application_id = $1`
+	deleteWhitelistMethodsScript   = `DELETE FROM whitelist_methods WHERE application_id = $1`
+	insertWhitelistContractScript  = `
+	INSERT into whitelist_contracts (application_id, blockchain_id, address)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (application_id, blockchain_id, address) DO NOTHING`
+	insertWhitelistMethodScript = `
+	INSERT into whitelist_methods (application_id, blockchain_id, method)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (application_id, blockchain_id, method) DO NOTHING`
+)
+
+// queryer is the part of a transaction or the database the whitelist reads need
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// ErrApplicationNotFound is returned when the application to update does not exist
+var ErrApplicationNotFound = types.ErrApplicationNotFound
+
+// UpdateWhitelist applies the change to the whitelists of the application in a single transaction, the application
+// row is locked first so concurrent changes are applied one after the other, the settings returned hold the whitelists only
+// and are returned along the new updated at of the application
+func (d *Driver) UpdateWhitelist(id string, change *types.WhitelistChange) (*repository.GatewaySettings, time.Time, error) {
+	if id == "" {
+		return nil, time.Time{}, postgresdriver.ErrMissingID
+	}
+
+	err := change.Validate()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	updatedAt := updateTime()
+
+	result, err := tx.Exec(touchApplicationScript, updatedAt, id)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if affected == 0 {
+		return nil, time.Time{}, ErrApplicationNotFound
+	}
+
+	var contracts, methods sql.NullString
+	var origins, userAgents, blockchains pq.StringArray
+
+	err = tx.QueryRow(selectWhitelistsScript, id).Scan(&contracts, &methods, &origins, &userAgents, &blockchains)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, err
+	}
+
+	settings := repository.GatewaySettings{
+		WhitelistOrigins:     origins,
+		WhitelistUserAgents:  userAgents,
+		WhitelistBlockchains: blockchains,
+	}
+
+	err = unmarshalWhitelists(contracts.String, methods.String, &settings)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	stored, err := readWhitelists(tx, id)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	stored.apply(id, &settings)
+
+	err = change.Apply(&settings)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	_, err = tx.Exec(upsertWhitelistsScript, id, pq.StringArray(settings.WhitelistOrigins),
+		pq.StringArray(settings.WhitelistUserAgents), pq.StringArray(settings.WhitelistBlockchains))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	err = replaceWhitelists(tx, id, settings)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return &settings, updatedAt, nil
+}
+
+// MigrateWhitelists moves the contracts and methods whitelists every application still keeps in its gateway
+// settings to their own tables as NormalizeWhitelists leaves them, the applications whose whitelists cannot be
+// parsed or are not valid are left untouched and reported
+func (d *Driver) MigrateWhitelists() (*types.WhitelistMigration, error) {
+	tx, err := d.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	type storedWhitelists struct {
+		applicationID      string
+		contracts, methods sql.NullString
+	}
+
+	rows, err := tx.Query(selectContractsAndMethodsScript)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored []storedWhitelists
+
+	for rows.Next() {
+		var whitelists storedWhitelists
+
+		err = rows.Scan(&whitelists.applicationID, &whitelists.contracts, &whitelists.methods)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		stored = append(stored, whitelists)
+	}
+
+	rows.Close()
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	migration := types.WhitelistMigration{Invalid: map[string]string{}}
+
+	for _, whitelists := range stored {
+		var settings repository.GatewaySettings
+
+		err = unmarshalWhitelists(whitelists.contracts.String, whitelists.methods.String, &settings)
+		if err == nil {
+			err = types.NormalizeWhitelists(&settings)
+		}
+		if err != nil {
+			migration.Invalid[whitelists.applicationID] = err.Error()
+			continue
+		}
+
+		err = moveWhitelists(tx, whitelists.applicationID, settings)
+		if err != nil {
+			return nil, err
+		}
+
+		migration.Migrated++
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &migration, nil
+}
+
+// storeWhitelists moves the contracts and methods whitelists an application was written with
+// from its gateway settings to their own tables
+func (d *Driver) storeWhitelists(id string, settings repository.GatewaySettings) error {
+	tx, err := d.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	err = moveWhitelists(tx, id, settings)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// moveWhitelists saves the contracts and methods whitelists in their own tables clearing the ones kept
+// in the gateway settings of the application
+func moveWhitelists(tx execer, id string, settings repository.GatewaySettings) error {
+	err := replaceWhitelists(tx, id, settings)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(clearContractsAndMethodsScript, id)
+
+	return err
+}
+
+// replaceWhitelists saves the contracts and methods whitelists of the application in their tables
+// one row per blockchain and value, replacing the ones saved before
+func replaceWhitelists(tx execer, id string, settings repository.GatewaySettings) error {
+	_, err := tx.Exec(deleteWhitelistContractsScript, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(deleteWhitelistMethodsScript, id)
+	if err != nil {
+		return err
+	}
+
+	for _, contract := range settings.WhitelistContracts {
+		for _, address := range contract.Contracts {
+			_, err = tx.Exec(insertWhitelistContractScript, id, contract.BlockchainID, address)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, method := range settings.WhitelistMethods {
+		for _, name := range method.Methods {
+			_, err = tx.Exec(insertWhitelistMethodScript, id, method.BlockchainID, name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// whitelists holds the contracts and methods whitelists of each application as saved in their tables
+type whitelists struct {
+	contracts map[string][]repository.WhitelistContract
+	methods   map[string][]repository.WhitelistMethod
+}
+
+// readWhitelists returns the contracts and methods whitelists saved in their tables for the
+// application, for every application when the ID is empty
+func readWhitelists(q queryer, id string) (*whitelists, error) {
+	stored := &whitelists{
+		contracts: make(map[string][]repository.WhitelistContract),
+		methods:   make(map[string][]repository.WhitelistMethod),
+	}
+
+	err := scanWhitelistRows(q, selectWhitelistContractsScript, id, func(appID, blockchainID, address string) {
+		stored.contracts[appID] = appendWhitelistContract(stored.contracts[appID], blockchainID, address)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanWhitelistRows(q, selectWhitelistMethodsScript, id, func(appID, blockchainID, method string) {
+		stored.methods[appID] = appendWhitelistMethod(stored.methods[appID], blockchainID, method)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// apply sets the contracts and methods whitelists saved in their tables on the settings of the application,
+// unless the settings still hold the ones written to the gateway settings which are yet to be moved there
+func (w *whitelists) apply(id string, settings *repository.GatewaySettings) {
+	if len(settings.WhitelistContracts) > 0 || len(settings.WhitelistMethods) > 0 {
+		return
+	}
+
+	settings.WhitelistContracts = w.contracts[id]
+	settings.WhitelistMethods = w.methods[id]
+}
+
+func scanWhitelistRows(q queryer, script, id string, add func(appID, blockchainID, value string)) error {
+	rows, err := q.Query(script, id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var appID, blockchainID, value string
+
+		err = rows.Scan(&appID, &blockchainID, &value)
+		if err != nil {
+			return err
+		}
+
+		add(appID, blockchainID, value)
+	}
+
+	return rows.Err()
+}
+
+func appendWhitelistContract(contracts []repository.WhitelistContract, blockchainID, address string) []repository.WhitelistContract {
+	for i := range contracts {
+		if contracts[i].BlockchainID == blockchainID {
+			contracts[i].Contracts = append(contracts[i].Contracts, address)
+			return contracts
+		}
+	}
+
+	return append(contracts, repository.WhitelistContract{BlockchainID: blockchainID, Contracts: []string{address}})
+}
+
+func appendWhitelistMethod(methods []repository.WhitelistMethod, blockchainID, method string) []repository.WhitelistMethod {
+	for i := range methods {
+		if methods[i].BlockchainID == blockchainID {
+			methods[i].Methods = append(methods[i].Methods, method)
+			return methods
+		}
+	}
+
+	return append(methods, repository.WhitelistMethod{BlockchainID: blockchainID, Methods: []string{method}})
+}
+
+// unmarshalWhitelists parses the contracts and methods whitelists as stored in the database into the settings
+func unmarshalWhitelists(contracts, methods string, settings *repository.GatewaySettings) error {
+	if contracts != "" {
+		err := json.Unmarshal([]byte(contracts), &settings.WhitelistContracts)
+		if err != nil {
+			return fmt.Errorf("whitelist contracts: %w", err)
+		}
+	}
+
+	if methods != "" {
+		err := json.Unmarshal([]byte(methods), &settings.WhitelistMethods)
+		if err != nil {
+			return fmt.Errorf("whitelist methods: %w", err)
+		}
+	}
+
+	return nil
+}
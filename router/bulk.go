@@ -0,0 +1,69 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+	"github.com/pokt-foundation/portal-api-go/repository"
+)
+
+// applicationChanges returns the top level JSON fields of the application that differ once updated
+func applicationChanges(app, updatedApp *repository.Application) (map[string]types.FieldChange, error) {
+	before, err := jsonFields(app)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := jsonFields(updatedApp)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]types.FieldChange{}
+
+	for field, value := range after {
+		if !bytes.Equal(before[field], value) {
+			changes[field] = types.FieldChange{From: before[field], To: value}
+		}
+	}
+
+	for field, value := range before {
+		if _, ok := after[field]; !ok {
+			changes[field] = types.FieldChange{From: value}
+		}
+	}
+
+	return changes, nil
+}
+
+func jsonFields(entity any) (map[string]json.RawMessage, error) {
+	rawEntity, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+
+	err = json.Unmarshal(rawEntity, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// uniqueIDs returns the IDs without repetitions, in the order they were first given
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	return unique
+}
@@ -0,0 +1,80 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Capability represents an extra permission granted to an API key
+type Capability string
+
+const (
+	// CapabilitySecretsRead allows reading application secrets and private keys
+	CapabilitySecretsRead Capability = "secrets:read"
+	// CapabilityQuotasOverride allows creating entities past the user quotas
+	CapabilityQuotasOverride Capability = "quotas:override"
+)
+
+var (
+	errInvalidCapabilities = errors.New("invalid api key capabilities")
+
+	knownCapabilities = map[Capability]bool{
+		CapabilitySecretsRead:    true,
+		CapabilityQuotasOverride: true,
+	}
+)
+
+type capabilitiesKey struct{}
+
+// ParseCapabilities parses the capabilities granted to each API key from a string with
+// the format "key1=capability|capability,key2=capability", keys without an entry get none
+func ParseCapabilities(rawCapabilities string) (map[string]map[Capability]bool, error) {
+	capabilities := make(map[string]map[Capability]bool)
+
+	for _, entry := range strings.Split(rawCapabilities, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, rawKeyCapabilities, found := strings.Cut(entry, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("%w: missing key on entry", errInvalidCapabilities)
+		}
+
+		keyCapabilities := capabilities[key]
+		if keyCapabilities == nil {
+			keyCapabilities = make(map[Capability]bool)
+			capabilities[key] = keyCapabilities
+		}
+
+		for _, rawCapability := range strings.Split(rawKeyCapabilities, "|") {
+			capability := Capability(strings.TrimSpace(rawCapability))
+
+			if !knownCapabilities[capability] {
+				return nil, fmt.Errorf("%w: unknown capability %q", errInvalidCapabilities, capability)
+			}
+
+			keyCapabilities[capability] = true
+		}
+	}
+
+	return capabilities, nil
+}
+
+// withCapabilities returns a copy of the request carrying the capabilities of its API key
+func (rt *Router) withCapabilities(r *http.Request) *http.Request {
+	capabilities := rt.Capabilities[r.Header.Get("Authorization")]
+
+	return r.WithContext(context.WithValue(r.Context(), capabilitiesKey{}, capabilities))
+}
+
+// hasCapability reports whether the request API key was granted the capability
+func hasCapability(r *http.Request, capability Capability) bool {
+	capabilities, _ := r.Context().Value(capabilitiesKey{}).(map[Capability]bool)
+
+	return capabilities[capability]
+}
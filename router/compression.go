@@ -0,0 +1,141 @@
+package router
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// compressResponseWriter compresses the response body with the negotiated encoding,
+// the compressor is only created once a body is written so empty responses stay empty
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding    string
+	compressor  io.WriteCloser
+	wroteHeader bool
+	compress    bool
+}
+
+func (cw *compressResponseWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+
+	cw.wroteHeader = true
+	cw.compress = bodyAllowed(code)
+
+	if cw.compress {
+		cw.Header().Set("Content-Encoding", cw.encoding)
+		cw.Header().Del("Content-Length")
+	}
+
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if !cw.compress {
+		return cw.ResponseWriter.Write(b)
+	}
+
+	if cw.compressor == nil {
+		switch cw.encoding {
+		case encodingBrotli:
+			cw.compressor = brotli.NewWriter(cw.ResponseWriter)
+		default:
+			cw.compressor = gzip.NewWriter(cw.ResponseWriter)
+		}
+	}
+
+	return cw.compressor.Write(b)
+}
+
+// Close flushes the compressed body, if any
+func (cw *compressResponseWriter) Close() error {
+	if cw.compressor == nil {
+		return nil
+	}
+
+	return cw.compressor.Close()
+}
+
+func bodyAllowed(code int) bool {
+	return code >= http.StatusOK && code != http.StatusNoContent && code != http.StatusNotModified
+}
+
+// negotiateEncoding returns the preferred supported encoding of an Accept-Encoding header,
+// brotli wins ties with gzip and an empty string means the response goes uncompressed
+func negotiateEncoding(acceptEncoding string) string {
+	var encoding string
+	var bestQuality float64
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if name != encodingBrotli && name != encodingGzip {
+			continue
+		}
+
+		quality := 1.0
+
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			parsed, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+			if err != nil {
+				continue
+			}
+
+			quality = parsed
+		}
+
+		if quality <= 0 {
+			continue
+		}
+
+		if quality > bestQuality || (quality == bestQuality && name == encodingBrotli) {
+			encoding, bestQuality = name, quality
+		}
+	}
+
+	return encoding
+}
+
+// CompressionHandler compresses responses with brotli or gzip as negotiated by the request Accept-Encoding header
+func (rt *Router) CompressionHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			h.ServeHTTP(w, r)
+
+			return
+		}
+
+		cw := &compressResponseWriter{
+			ResponseWriter: w,
+			encoding:       encoding,
+		}
+
+		defer func() {
+			err := cw.Close()
+			if err != nil {
+				rt.logError(err)
+			}
+		}()
+
+		h.ServeHTTP(cw, r)
+	})
+}
@@ -0,0 +1,62 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+	jsonresponse "github.com/pokt-foundation/utils-go/json-response"
+)
+
+var (
+	errInvalidIfMatch     = errors.New("invalid If-Match header")
+	errPreconditionFailed = errors.New("entity was modified since the given updatedAt")
+)
+
+// parseIfMatch returns the updatedAt the entity is expected to have from the If-Match header, holding it
+// as an RFC 3339 timestamp optionally quoted, ok is false when the request has no precondition
+func parseIfMatch(r *http.Request) (updatedAt time.Time, ok bool, err error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return time.Time{}, false, nil
+	}
+
+	ifMatch = strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+
+	updatedAt, err = time.Parse(time.RFC3339Nano, ifMatch)
+	if err != nil {
+		return time.Time{}, false, errInvalidIfMatch
+	}
+
+	return updatedAt, true, nil
+}
+
+// ifMatch returns the updated at the entity must still have for the request to go through, nil when the
+// request has no precondition, it responds and returns false when the If-Match header is invalid
+func ifMatch(w http.ResponseWriter, r *http.Request) (*time.Time, bool) {
+	updatedAt, ok, err := parseIfMatch(r)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	if !ok {
+		return nil, true
+	}
+
+	return &updatedAt, true
+}
+
+// preconditionFailed responds and returns true when the write failed as the entity
+// no longer has the updated at the request expected
+func preconditionFailed(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, types.ErrApplicationChanged) && !errors.Is(err, types.ErrLoadBalancerChanged) {
+		return false
+	}
+
+	jsonresponse.RespondWithError(w, http.StatusPreconditionFailed, errPreconditionFailed.Error())
+
+	return true
+}
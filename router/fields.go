@@ -0,0 +1,143 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	errInvalidFields      = errors.New("invalid fields")
+	errFieldsNotSupported = errors.New("fields are not supported on this endpoint")
+)
+
+// fieldSet is the tree of JSON fields to keep on a response, a nil subtree keeps the whole field
+type fieldSet map[string]fieldSet
+
+// parseFields parses a comma separated list of JSON field names, nested fields are
+// selected with dots as in "limit.payPlan.planType", an empty list selects every field
+func parseFields(rawFields string) (fieldSet, error) {
+	if strings.TrimSpace(rawFields) == "" {
+		return nil, nil
+	}
+
+	fields := fieldSet{}
+
+	for _, path := range strings.Split(rawFields, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		node := fields
+		segments := strings.Split(path, ".")
+
+		for i, segment := range segments {
+			if segment == "" {
+				return nil, fmt.Errorf("%w: %s", errInvalidFields, path)
+			}
+
+			if i == len(segments)-1 {
+				node[segment] = nil
+				break
+			}
+
+			child, ok := node[segment]
+			if ok && child == nil {
+				break // the whole field is already selected
+			}
+
+			if !ok {
+				child = fieldSet{}
+				node[segment] = child
+			}
+
+			node = child
+		}
+	}
+
+	return fields, nil
+}
+
+// project keeps only the selected fields of a JSON object, arrays are projected
+// element by element and any other value is returned untouched
+func (f fieldSet) project(raw json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return raw, nil
+	}
+
+	switch trimmed[0] {
+	case '{':
+		var object map[string]json.RawMessage
+
+		err := json.Unmarshal(trimmed, &object)
+		if err != nil {
+			return nil, err
+		}
+
+		projected := make(map[string]json.RawMessage, len(f))
+
+		for name, subFields := range f {
+			value, ok := object[name]
+			if !ok {
+				continue
+			}
+
+			if subFields != nil {
+				value, err = subFields.project(value)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			projected[name] = value
+		}
+
+		return json.Marshal(projected)
+	case '[':
+		var array []json.RawMessage
+
+		err := json.Unmarshal(trimmed, &array)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, element := range array {
+			array[i], err = f.project(element)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return json.Marshal(array)
+	default:
+		return raw, nil
+	}
+}
+
+// projectMembers projects the given members of a JSON object holding entities, keeping the rest of the object
+func (f fieldSet) projectMembers(raw json.RawMessage, members []string) (json.RawMessage, error) {
+	var object map[string]json.RawMessage
+
+	err := json.Unmarshal(raw, &object)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, member := range members {
+		value, ok := object[member]
+		if !ok {
+			continue
+		}
+
+		object[member], err = f.project(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(object)
+}
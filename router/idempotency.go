@@ -0,0 +1,167 @@
+package router
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+	jsonresponse "github.com/pokt-foundation/utils-go/json-response"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+
+	// DefaultIdempotencyTTL is how long the responses of requests with an idempotency key are replayed
+	DefaultIdempotencyTTL = 24 * time.Hour
+	// DefaultIdempotencyLockTimeout is how long an idempotency key is held by a request whose response is not saved
+	DefaultIdempotencyLockTimeout = time.Minute
+)
+
+var (
+	errInvalidIdempotencyKey = errors.New("invalid idempotency key")
+	errIdempotencyKeyInUse   = errors.New("a request with the idempotency key is still being handled")
+	errIdempotencyKeyReused  = errors.New("idempotency key already used for a different request")
+)
+
+// idempotentResponseWriter holds the response back so it can be saved before being sent
+type idempotentResponseWriter struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (iw *idempotentResponseWriter) WriteHeader(code int) {
+	if iw.code == 0 {
+		iw.code = code
+	}
+}
+
+func (iw *idempotentResponseWriter) Write(b []byte) (int, error) {
+	if iw.code == 0 {
+		iw.code = http.StatusOK
+	}
+
+	return iw.body.Write(b)
+}
+
+// hashRequest returns the hash identifying the method, URI and body of the request
+func hashRequest(r *http.Request, body []byte) string {
+	hash := sha256.New()
+
+	fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.RequestURI())
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// hashCaller returns the hash of the request API key so keys of different callers never collide
+func hashCaller(r *http.Request) string {
+	hash := sha256.Sum256([]byte(r.Header.Get("Authorization")))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// IdempotencyHandler replays the saved response of POST requests retried with the same Idempotency-Key
+// header, the key is shared by all the instances through the database, server errors are not saved
+// so the request can be retried, as are the requests whose response is not saved within the lock timeout
+func (rt *Router) IdempotencyHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			h.ServeHTTP(w, r)
+
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			jsonresponse.RespondWithError(w, http.StatusBadRequest, errInvalidIdempotencyKey.Error())
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		caller, requestHash := hashCaller(r), hashRequest(r, body)
+
+		saved, err := rt.Writer.ReserveIdempotencyKey(key, caller, requestHash, rt.IdempotencyTTL, rt.IdempotencyLockTimeout)
+		if err != nil {
+			rt.logError(fmt.Errorf("ReserveIdempotencyKey failed: %w", err))
+			jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if saved != nil {
+			rt.replayIdempotentRequest(w, saved, requestHash)
+			return
+		}
+
+		iw := &idempotentResponseWriter{ResponseWriter: w}
+
+		h.ServeHTTP(iw, r)
+
+		if iw.code == 0 {
+			iw.code = http.StatusOK
+		}
+
+		response := &types.IdempotentRequest{
+			RequestHash: requestHash,
+			StatusCode:  iw.code,
+			ContentType: w.Header().Get("Content-Type"),
+			Body:        iw.body.Bytes(),
+		}
+
+		if iw.code >= http.StatusInternalServerError {
+			err = rt.Writer.ReleaseIdempotencyKey(key, caller)
+		} else {
+			err = rt.Writer.SaveIdempotentResponse(key, caller, response)
+		}
+		if err != nil {
+			rt.logError(fmt.Errorf("saving idempotent response failed: %w", err))
+		}
+
+		w.WriteHeader(iw.code)
+
+		_, err = w.Write(iw.body.Bytes())
+		if err != nil {
+			rt.logError(fmt.Errorf("IdempotencyHandler write failed: %w", err))
+		}
+	})
+}
+
+// replayIdempotentRequest answers with the saved response, as long as the request is the one it belongs to
+func (rt *Router) replayIdempotentRequest(w http.ResponseWriter, saved *types.IdempotentRequest, requestHash string) {
+	if saved.RequestHash != requestHash {
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, errIdempotencyKeyReused.Error())
+		return
+	}
+
+	if saved.StatusCode == 0 {
+		jsonresponse.RespondWithError(w, http.StatusConflict, errIdempotencyKeyInUse.Error())
+		return
+	}
+
+	if saved.ContentType != "" {
+		w.Header().Set("Content-Type", saved.ContentType)
+	}
+
+	w.Header().Set(idempotentReplayedHeader, "true")
+	w.WriteHeader(saved.StatusCode)
+
+	_, err := w.Write(saved.Body)
+	if err != nil {
+		rt.logError(fmt.Errorf("replayIdempotentRequest write failed: %w", err))
+	}
+}
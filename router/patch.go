@@ -0,0 +1,142 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	jsonresponse "github.com/pokt-foundation/utils-go/json-response"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+var (
+	errUnsupportedPatch  = errors.New("patches must be JSON merge patches")
+	errInvalidPatch      = errors.New("patch must be a JSON object")
+	errFieldNotPatchable = errors.New("field cannot be patched")
+)
+
+// patchableApplicationFields are the application fields a merge patch can change, the same UpdateApplication does
+var patchableApplicationFields = map[string]bool{
+	"name":                 true,
+	"status":               true,
+	"firstDateSurpassed":   true,
+	"limit":                true,
+	"gatewaySettings":      true,
+	"notificationSettings": true,
+}
+
+// patchableLoadBalancerFields are the load balancer fields a merge patch can change, the same UpdateLoadBalancer does
+var patchableLoadBalancerFields = map[string]bool{
+	"name":              true,
+	"stickinessOptions": true,
+}
+
+// mergePatch applies the RFC 7386 JSON merge patch to the target document, nulls remove the members
+func mergePatch(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any)
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+
+	return targetObject
+}
+
+func decodeJSON(rawJSON []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(rawJSON))
+	decoder.UseNumber()
+
+	var document any
+
+	err := decoder.Decode(&document)
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// decodeMergePatch applies the merge patch on the request body to the entity and decodes the result into patched,
+// only the patchable top level fields can be changed, it responds and returns false when the patch is not valid
+func (rt *Router) decodeMergePatch(w http.ResponseWriter, r *http.Request, entity any, patchable map[string]bool, patched any) bool {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergePatchContentType && mediaType != "application/json") {
+			jsonresponse.RespondWithError(w, http.StatusUnsupportedMediaType, errUnsupportedPatch.Error())
+			return false
+		}
+	}
+
+	rawPatch, err := io.ReadAll(r.Body)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	defer r.Body.Close()
+
+	patch, err := decodeJSON(rawPatch)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, errInvalidPatch.Error())
+		return false
+	}
+
+	for field := range patchObject {
+		if !patchable[field] {
+			jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, fmt.Errorf("%w: %s", errFieldNotPatchable, field).Error())
+			return false
+		}
+	}
+
+	rawEntity, err := json.Marshal(entity)
+	if err != nil {
+		rt.logError(fmt.Errorf("decodeMergePatch marshal failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	target, err := decodeJSON(rawEntity)
+	if err != nil {
+		rt.logError(fmt.Errorf("decodeMergePatch decode failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	rawPatched, err := json.Marshal(mergePatch(target, patchObject))
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	err = json.Unmarshal(rawPatched, patched)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,357 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+	"github.com/pokt-foundation/portal-api-go/repository"
+	jsonresponse "github.com/pokt-foundation/utils-go/json-response"
+)
+
+var (
+	errInvalidQuotas        = errors.New("invalid quotas")
+	errInvalidOverrideQuota = errors.New("invalid override_quota")
+	errOverrideNotAllowed   = errors.New("api key not allowed to override quotas")
+	errApplicationQuota     = errors.New("user reached its applications quota")
+	errPayPlanQuota         = errors.New("user reached its applications quota for the pay plan")
+	errLoadBalancerQuota    = errors.New("user reached its load balancers quota")
+	errQuotaLocked          = errors.New("quotas of the user are locked by another request")
+)
+
+// Quota is the maximum number of applications and load balancers a user can own, zero means no limit
+type Quota struct {
+	Applications  int
+	LoadBalancers int
+}
+
+// Quotas holds the limits enforced when users get applications and load balancers, by creating, restoring or
+// transferring them, and when their applications change pay plan, the quota of a user replaces both the default
+// quota and the pay plan quotas for that user
+type Quotas struct {
+	Default Quota
+	// PayPlans holds the maximum number of applications of each pay plan a user can own
+	PayPlans map[repository.PayPlanType]int
+	Users    map[string]Quota
+}
+
+// limited reports whether any quota applies to the user
+func (q Quotas) limited(userID string) bool {
+	if quota, ok := q.Users[userID]; ok {
+		return quota.Applications > 0 || quota.LoadBalancers > 0
+	}
+
+	if q.Default.Applications > 0 || q.Default.LoadBalancers > 0 {
+		return true
+	}
+
+	for _, quota := range q.PayPlans {
+		if quota > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParsePayPlanQuotas parses the applications quota of each pay plan from a string
+// with the format "FREETIER_V0=2,PAY_AS_YOU_GO_V0=10"
+func ParsePayPlanQuotas(rawQuotas string) (map[repository.PayPlanType]int, error) {
+	quotas := make(map[repository.PayPlanType]int)
+
+	for _, entry := range strings.Split(rawQuotas, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		rawPlan, rawQuota, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: missing quota on entry %q", errInvalidQuotas, entry)
+		}
+
+		plan := repository.PayPlanType(strings.TrimSpace(rawPlan))
+		if plan == "" || !repository.ValidPayPlanTypes[plan] {
+			return nil, fmt.Errorf("%w: unknown pay plan %q", errInvalidQuotas, plan)
+		}
+
+		quota, err := parseQuota(rawQuota)
+		if err != nil {
+			return nil, err
+		}
+
+		quotas[plan] = quota
+	}
+
+	return quotas, nil
+}
+
+// ParseUserQuotas parses the quota of each user from a string with the format
+// "user1=10:5,user2=20:10", holding the applications and load balancers quotas
+func ParseUserQuotas(rawQuotas string) (map[string]Quota, error) {
+	quotas := make(map[string]Quota)
+
+	for _, entry := range strings.Split(rawQuotas, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		userID, rawQuota, found := strings.Cut(entry, "=")
+		if !found || strings.TrimSpace(userID) == "" {
+			return nil, fmt.Errorf("%w: missing user on entry %q", errInvalidQuotas, entry)
+		}
+
+		rawApplications, rawLoadBalancers, found := strings.Cut(rawQuota, ":")
+		if !found {
+			return nil, fmt.Errorf("%w: missing load balancers quota on entry %q", errInvalidQuotas, entry)
+		}
+
+		applications, err := parseQuota(rawApplications)
+		if err != nil {
+			return nil, err
+		}
+
+		loadBalancers, err := parseQuota(rawLoadBalancers)
+		if err != nil {
+			return nil, err
+		}
+
+		quotas[strings.TrimSpace(userID)] = Quota{Applications: applications, LoadBalancers: loadBalancers}
+	}
+
+	return quotas, nil
+}
+
+func parseQuota(rawQuota string) (int, error) {
+	quota, err := strconv.Atoi(strings.TrimSpace(rawQuota))
+	if err != nil || quota < 0 {
+		return 0, fmt.Errorf("%w: invalid quota %q", errInvalidQuotas, rawQuota)
+	}
+
+	return quota, nil
+}
+
+// parseOverrideQuota reports whether the request asked to skip the quotas, only
+// API keys with the quotas override capability are allowed to do so
+func parseOverrideQuota(r *http.Request) (bool, error) {
+	rawOverride := r.URL.Query().Get("override_quota")
+	if rawOverride == "" {
+		return false, nil
+	}
+
+	override, err := strconv.ParseBool(rawOverride)
+	if err != nil {
+		return false, errInvalidOverrideQuota
+	}
+
+	if override && !hasCapability(r, CapabilityQuotasOverride) {
+		return false, errOverrideNotAllowed
+	}
+
+	return override, nil
+}
+
+// userQuotaLockTimeout is how long a request waits for the quotas of a user locked by another request
+const userQuotaLockTimeout = 10 * time.Second
+
+// quotaChange is what a request adds to the entities a user owns
+type quotaChange struct {
+	// applications holds the pay plan of each application the user gets
+	applications []repository.PayPlanType
+	// payPlanChanges holds the pay plan each application the user already owns moves to
+	payPlanChanges []repository.PayPlanType
+	loadBalancers  int
+}
+
+func (c quotaChange) empty() bool {
+	return len(c.applications) == 0 && len(c.payPlanChanges) == 0 && c.loadBalancers == 0
+}
+
+func (c quotaChange) add(other quotaChange) quotaChange {
+	return quotaChange{
+		applications:   append(c.applications, other.applications...),
+		payPlanChanges: append(c.payPlanChanges, other.payPlanChanges...),
+		loadBalancers:  c.loadBalancers + other.loadBalancers,
+	}
+}
+
+// applicationQuotaChange returns what updating the application adds to the entities its user owns, applications
+// awaiting their grace period are not counted so putting them back in service adds them
+func applicationQuotaChange(app, updatedApp *repository.Application) quotaChange {
+	counted := app.Status != repository.AwaitingGracePeriod
+	countedOnceUpdated := updatedApp.Status != repository.AwaitingGracePeriod
+
+	switch {
+	case countedOnceUpdated && !counted:
+		return quotaChange{applications: []repository.PayPlanType{updatedApp.Limit.PayPlan.Type}}
+	case countedOnceUpdated && updatedApp.Limit.PayPlan.Type != app.Limit.PayPlan.Type:
+		return quotaChange{payPlanChanges: []repository.PayPlanType{updatedApp.Limit.PayPlan.Type}}
+	default:
+		return quotaChange{}
+	}
+}
+
+// countsQuotaChange returns what a user gets when every entity of the counts is transferred to them
+func countsQuotaChange(counts *types.UserEntityCounts) quotaChange {
+	change := quotaChange{loadBalancers: counts.LoadBalancers}
+
+	for payPlan, count := range counts.Applications {
+		for i := 0; i < count; i++ {
+			change.applications = append(change.applications, payPlan)
+		}
+	}
+
+	return change
+}
+
+// enforceQuotas checks the user can own the entities the change adds, responding when it cannot, the quotas of the
+// user stay locked until the returned function is called once the entities are written so concurrent requests are
+// checked one after the other against the entities in the database
+func (rt *Router) enforceQuotas(w http.ResponseWriter, r *http.Request, userID string, change quotaChange) (func(), bool) {
+	overrideQuota, err := parseOverrideQuota(r)
+	if errors.Is(err, errOverrideNotAllowed) {
+		jsonresponse.RespondWithError(w, http.StatusForbidden, err.Error())
+		return nil, false
+	}
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	if overrideQuota || userID == "" || change.empty() || !rt.Quotas.limited(userID) {
+		return func() {}, true
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), userQuotaLockTimeout)
+	defer cancel()
+
+	unlock, err := rt.Writer.LockUserQuota(ctx, userID)
+	if errors.Is(err, context.DeadlineExceeded) {
+		rt.logError(fmt.Errorf("LockUserQuota in enforceQuotas failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusServiceUnavailable, errQuotaLocked.Error())
+		return nil, false
+	}
+	if err != nil {
+		rt.logError(fmt.Errorf("LockUserQuota in enforceQuotas failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	counts, err := rt.Writer.ReadUserEntityCounts(userID)
+	if err != nil {
+		unlock()
+		rt.logError(fmt.Errorf("ReadUserEntityCounts in enforceQuotas failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	err = rt.checkApplicationQuota(userID, counts, change)
+	if err == nil && change.loadBalancers > 0 {
+		err = rt.checkLoadBalancerQuota(userID, counts, change.loadBalancers)
+	}
+	if err != nil {
+		unlock()
+		jsonresponse.RespondWithError(w, http.StatusConflict, err.Error())
+		return nil, false
+	}
+
+	return unlock, true
+}
+
+// enforceUsersQuotas enforces the quotas of each user as enforceQuotas does, the users are locked
+// in order so requests changing the entities of the same users do not wait for each other forever
+func (rt *Router) enforceUsersQuotas(w http.ResponseWriter, r *http.Request, changes map[string]quotaChange) (func(), bool) {
+	userIDs := make([]string, 0, len(changes))
+	for userID := range changes {
+		userIDs = append(userIDs, userID)
+	}
+
+	sort.Strings(userIDs)
+
+	var unlocks []func()
+
+	unlockAll := func() {
+		for _, unlock := range unlocks {
+			unlock()
+		}
+	}
+
+	for _, userID := range userIDs {
+		unlock, ok := rt.enforceQuotas(w, r, userID, changes[userID])
+		if !ok {
+			unlockAll()
+			return nil, false
+		}
+
+		unlocks = append(unlocks, unlock)
+	}
+
+	return unlockAll, true
+}
+
+// checkApplicationQuota returns an error when the user cannot own the applications the change adds, or
+// the applications of the pay plans its applications move to
+func (rt *Router) checkApplicationQuota(userID string, counts *types.UserEntityCounts, change quotaChange) error {
+	quota, hasUserQuota := rt.Quotas.Users[userID]
+	if !hasUserQuota {
+		quota = rt.Quotas.Default
+	}
+
+	total := len(change.applications)
+	ofPayPlan := make(map[repository.PayPlanType]int)
+
+	for _, payPlan := range change.applications {
+		ofPayPlan[payPlan]++
+	}
+
+	for _, payPlan := range change.payPlanChanges {
+		ofPayPlan[payPlan]++
+	}
+
+	for payPlan, count := range counts.Applications {
+		total += count
+
+		if _, ok := ofPayPlan[payPlan]; ok {
+			ofPayPlan[payPlan] += count
+		}
+	}
+
+	// moving applications between pay plans leaves the number of applications as it is
+	if quota.Applications > 0 && len(change.applications) > 0 && total > quota.Applications {
+		return errApplicationQuota
+	}
+
+	if hasUserQuota {
+		return nil
+	}
+
+	for payPlan, count := range ofPayPlan {
+		planQuota := rt.Quotas.PayPlans[payPlan]
+		if planQuota > 0 && count > planQuota {
+			return errPayPlanQuota
+		}
+	}
+
+	return nil
+}
+
+// checkLoadBalancerQuota returns an error when the user cannot own the new load balancers
+func (rt *Router) checkLoadBalancerQuota(userID string, counts *types.UserEntityCounts, loadBalancers int) error {
+	quota, ok := rt.Quotas.Users[userID]
+	if !ok {
+		quota = rt.Quotas.Default
+	}
+
+	if quota.LoadBalancers > 0 && counts.LoadBalancers+loadBalancers > quota.LoadBalancers {
+		return errLoadBalancerQuota
+	}
+
+	return nil
+}
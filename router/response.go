@@ -0,0 +1,254 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/pokt-foundation/pocket-http-db/types"
+	"github.com/pokt-foundation/portal-api-go/repository"
+	jsonresponse "github.com/pokt-foundation/utils-go/json-response"
+)
+
+// encoder encodes response payloads as requested by the caller
+type encoder struct {
+	fields fieldSet
+	redact bool
+}
+
+// newEncoder returns the encoder for the request, applying the fields query parameter
+// and hiding secrets unless the caller API key can read them
+func newEncoder(r *http.Request) (encoder, error) {
+	fields, err := parseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		return encoder{}, err
+	}
+
+	return encoder{
+		fields: fields,
+		redact: !hasCapability(r, CapabilitySecretsRead),
+	}, nil
+}
+
+func (e encoder) encode(payload any) ([]byte, error) {
+	if e.redact {
+		payload = redactSecrets(payload)
+	}
+
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.fields == nil {
+		return rawPayload, nil
+	}
+
+	members, err := entityMembers(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if members != nil {
+		return e.fields.projectMembers(rawPayload, members)
+	}
+
+	return e.fields.project(rawPayload)
+}
+
+// entityMembers returns the JSON members holding the entities of wrapper payloads, which are the ones the
+// fields apply to, payloads that are entities or lists of them have no members and are projected as a whole
+func entityMembers(payload any) ([]string, error) {
+	switch payload.(type) {
+	case types.BatchApplications:
+		return []string{"applications"}, nil
+	case types.BatchLoadBalancers:
+		return []string{"loadBalancers"}, nil
+	case types.User:
+		return []string{"applications", "loadBalancers"}, nil
+	case types.Changes:
+		return []string{"applications", "loadBalancers", "blockchains"}, nil
+	case types.BulkUpdateResult, []types.UsageStateResult:
+		return nil, errFieldsNotSupported
+	default:
+		return nil, nil
+	}
+}
+
+// respond is the single place responses holding applications or load balancers are written,
+// making sure secrets are redacted and the requested fields projected
+func (rt *Router) respond(w http.ResponseWriter, r *http.Request, code int, payload any) {
+	enc, err := newEncoder(r)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rawPayload, err := enc.encode(payload)
+	if errors.Is(err, errFieldsNotSupported) {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		rt.logError(fmt.Errorf("respond encode failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	jsonresponse.RespondWithJSON(w, code, json.RawMessage(rawPayload))
+}
+
+// respondList is the streaming counterpart of respond for lists
+func respondList[T any](rt *Router, w http.ResponseWriter, r *http.Request, items []T) {
+	enc, err := newEncoder(r)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = respondWithJSONList(w, http.StatusOK, items, enc)
+	if err != nil {
+		rt.logError(fmt.Errorf("respondList write failed: %w", err))
+	}
+}
+
+// redactSecrets returns a copy of the payload without application secrets, payloads holding
+// no applications are returned as they are, cached entities are never modified
+func redactSecrets(payload any) any {
+	switch value := payload.(type) {
+	case *repository.Application:
+		return redactApplication(value)
+	case []*repository.Application:
+		return redactApplications(value)
+	case *repository.LoadBalancer:
+		return redactLoadBalancer(value)
+	case []*repository.LoadBalancer:
+		return redactLoadBalancers(value)
+	case *types.LoadBalancer:
+		return &types.LoadBalancer{LoadBalancer: redactLoadBalancer(value.LoadBalancer), RemovedAt: value.RemovedAt}
+	case types.BatchApplications:
+		value.Applications = redactApplications(value.Applications)
+		return value
+	case types.BatchLoadBalancers:
+		value.LoadBalancers = redactLoadBalancers(value.LoadBalancers)
+		return value
+	case types.User:
+		value.Applications = redactApplications(value.Applications)
+		value.LoadBalancers = redactLoadBalancers(value.LoadBalancers)
+		return value
+	case types.Changes:
+		value.Applications = redactApplications(value.Applications)
+		value.LoadBalancers = redactLoadBalancers(value.LoadBalancers)
+		return value
+	case types.BulkUpdateResult:
+		value.Updated = redactBulkUpdatedApplications(value.Updated)
+		return value
+	default:
+		return payload
+	}
+}
+
+func redactApplication(app *repository.Application) *repository.Application {
+	if app == nil {
+		return nil
+	}
+
+	redacted := *app
+	redacted.GatewaySettings.SecretKey = ""
+	redacted.GatewayAAT.PrivateKey = ""
+
+	return &redacted
+}
+
+func redactApplications(apps []*repository.Application) []*repository.Application {
+	if apps == nil {
+		return nil
+	}
+
+	redacted := make([]*repository.Application, 0, len(apps))
+	for _, app := range apps {
+		redacted = append(redacted, redactApplication(app))
+	}
+
+	return redacted
+}
+
+func redactLoadBalancer(lb *repository.LoadBalancer) *repository.LoadBalancer {
+	if lb == nil {
+		return nil
+	}
+
+	redacted := *lb
+	redacted.Applications = redactApplications(lb.Applications)
+
+	return &redacted
+}
+
+func redactLoadBalancers(lbs []*repository.LoadBalancer) []*repository.LoadBalancer {
+	if lbs == nil {
+		return nil
+	}
+
+	redacted := make([]*repository.LoadBalancer, 0, len(lbs))
+	for _, lb := range lbs {
+		redacted = append(redacted, redactLoadBalancer(lb))
+	}
+
+	return redacted
+}
+
+// secretMembers are the members holding secrets of the application fields reported by bulk updates
+var secretMembers = map[string]string{
+	"gatewaySettings": "secretKey",
+	"gatewayAAT":      "privateKey",
+}
+
+func redactBulkUpdatedApplications(updated []types.BulkUpdatedApplication) []types.BulkUpdatedApplication {
+	if updated == nil {
+		return nil
+	}
+
+	redacted := make([]types.BulkUpdatedApplication, 0, len(updated))
+	for _, app := range updated {
+		changes := make(map[string]types.FieldChange, len(app.Changes))
+		for field, change := range app.Changes {
+			if member, ok := secretMembers[field]; ok {
+				change.From = redactMember(change.From, member)
+				change.To = redactMember(change.To, member)
+			}
+
+			changes[field] = change
+		}
+
+		redacted = append(redacted, types.BulkUpdatedApplication{ApplicationID: app.ApplicationID, Changes: changes})
+	}
+
+	return redacted
+}
+
+// redactMember returns the JSON object with the member blanked, values that are not objects are dropped
+// as the secret could not be told apart
+func redactMember(rawObject json.RawMessage, member string) json.RawMessage {
+	if rawObject == nil {
+		return nil
+	}
+
+	var object map[string]json.RawMessage
+
+	err := json.Unmarshal(rawObject, &object)
+	if err != nil || object == nil {
+		return nil
+	}
+
+	if _, ok := object[member]; ok {
+		object[member] = json.RawMessage(`""`)
+	}
+
+	redacted, err := json.Marshal(object)
+	if err != nil {
+		return nil
+	}
+
+	return redacted
+}
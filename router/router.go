@@ -1,38 +1,85 @@
 package router
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pokt-foundation/pocket-http-db/aat"
 	"github.com/pokt-foundation/pocket-http-db/cache"
+	"github.com/pokt-foundation/pocket-http-db/types"
 	"github.com/pokt-foundation/portal-api-go/repository"
 	jsonresponse "github.com/pokt-foundation/utils-go/json-response"
+	"github.com/pokt-foundation/utils-go/random"
 	"github.com/sirupsen/logrus"
 )
 
+// secretKeyLength is the number of hex characters of the generated gateway secret keys
+const secretKeyLength = 32
+
 var (
-	errNoPayFound          = errors.New("pay plan not found")
-	errBalancerNotFound    = errors.New("load balancer not found")
-	errBlockchainNotFound  = errors.New("blockchain not found")
-	errApplicationNotFound = errors.New("applications not found")
+	errNoPayFound             = errors.New("pay plan not found")
+	errBalancerNotFound       = errors.New("load balancer not found")
+	errBlockchainNotFound     = errors.New("blockchain not found")
+	errApplicationNotFound    = errors.New("applications not found")
+	errRedirectNotFound       = errors.New("redirect not found")
+	errAliasInUse             = errors.New("blockchain alias already in use")
+	errAliasRepeated          = errors.New("blockchain alias repeated")
+	errNoPublicKeys           = errors.New("no public keys on input")
+	errNoIDs                  = errors.New("no ids on input")
+	errInvalidVersion         = errors.New("invalid version")
+	errApplicationNotRemoved  = errors.New("application is not awaiting grace period")
+	errLoadBalancerRemoved    = errors.New("load balancer is removed")
+	errLoadBalancerNotRemoved = errors.New("load balancer is not removed")
+	errInvalidIncludeRemoved  = errors.New("invalid include_removed")
+	errNoUserID               = errors.New("no user id on input")
+	errUserNotFound           = errors.New("user not found")
+	errNoApplications         = errors.New("no applications on input")
+	errSecretsReadRequired    = errors.New("api key cannot read secrets")
+	errAATKeyMismatch         = errors.New("application private key does not match its staked public key")
 )
 
 // Writer represents the implementation of writer interface
 type Writer interface {
 	WriteLoadBalancer(loadBalancer *repository.LoadBalancer) (*repository.LoadBalancer, error)
-	UpdateLoadBalancer(id string, options *repository.UpdateLoadBalancer) error
-	RemoveLoadBalancer(id string) error
+	UpdateLoadBalancer(id string, options *repository.UpdateLoadBalancer, ifUpdatedAt *time.Time) (time.Time, error)
+	RemoveLoadBalancer(id string, ifUpdatedAt *time.Time) (time.Time, error)
+	RestoreLoadBalancer(id string) (*types.LoadBalancerRemoval, error)
+	TransferLoadBalancer(id, userID string) error
+	TransferApplication(id, userID string) error
+	TransferUserEntities(fromUserID, toUserID string) (*types.TransferredEntities, error)
+	WriteEndpoint(endpoint *types.Endpoint) (*types.Endpoint, error)
+	ReserveIdempotencyKey(key, caller, requestHash string, ttl, lockTimeout time.Duration) (*types.IdempotentRequest, error)
+	SaveIdempotentResponse(key, caller string, response *types.IdempotentRequest) error
+	ReleaseIdempotencyKey(key, caller string) error
+	LockUserQuota(ctx context.Context, userID string) (func(), error)
+	ReadUserEntityCounts(userID string) (*types.UserEntityCounts, error)
+	PatchApplication(app *repository.Application, ifUpdatedAt *time.Time) (time.Time, error)
+	PatchApplications(apps []*repository.Application) error
+	PatchLoadBalancer(lb *repository.LoadBalancer, ifUpdatedAt *time.Time) (time.Time, error)
+	UpdateWhitelist(id string, change *types.WhitelistChange) (*repository.GatewaySettings, time.Time, error)
 	WriteApplication(app *repository.Application) (*repository.Application, error)
-	UpdateApplication(id string, options *repository.UpdateApplication) error
+	UpdateApplication(id string, options *repository.UpdateApplication, ifUpdatedAt *time.Time) (time.Time, error)
+	UpdateGatewayAAT(id string, aat *repository.GatewayAAT) error
+	UpdateSecretKey(id, secretKey string) error
 	UpdateFirstDateSurpassed(firstDateSurpassed *repository.UpdateFirstDateSurpassed) error
-	RemoveApplication(id string) error
+	ReadUsageState(id string) (*types.UsageState, error)
+	UpdateUsageStates(updates []*types.UsageStateUpdate) ([]*types.UsageState, []error, error)
+	RemoveApplication(id string, ifUpdatedAt *time.Time) (time.Time, error)
+	RestoreApplication(id string) (*types.ApplicationRemoval, error)
 	WriteBlockchain(blockchain *repository.Blockchain) (*repository.Blockchain, error)
 	WriteRedirect(redirect *repository.Redirect) (*repository.Redirect, error)
 	ActivateBlockchain(id string, active bool) error
+	UpdateBlockchain(id string, options *types.UpdateBlockchain) error
 }
 
 // Router struct handler for router requests
@@ -41,7 +88,19 @@ type Router struct {
 	Router  *mux.Router
 	Writer  Writer
 	APIKeys map[string]bool
-	log     *logrus.Logger
+	// Capabilities holds the extra permissions granted to each API key
+	Capabilities map[string]map[Capability]bool
+	// Quotas holds the limits on the entities each user can create
+	Quotas Quotas
+	// IdempotencyTTL is how long the responses of requests with an idempotency key are replayed
+	IdempotencyTTL time.Duration
+	// IdempotencyLockTimeout is how long a request holds its idempotency key before it can be retried
+	IdempotencyLockTimeout time.Duration
+	log                    *logrus.Logger
+
+	// instanceID distinguishes the ETags of different instances and restarts
+	// as cache generations are only meaningful within a single process
+	instanceID string
 }
 
 func (rt *Router) logError(err error) {
@@ -53,7 +112,7 @@ func (rt *Router) logError(err error) {
 }
 
 // NewRouter returns router instance
-func NewRouter(reader cache.Reader, writer Writer, apiKeys map[string]bool, logger *logrus.Logger) (*Router, error) {
+func NewRouter(reader cache.Reader, writer Writer, apiKeys map[string]bool, capabilities map[string]map[Capability]bool, quotas Quotas, logger *logrus.Logger) (*Router, error) {
 	cache := cache.NewCache(reader, logger)
 
 	err := cache.SetCache()
@@ -61,36 +120,78 @@ func NewRouter(reader cache.Reader, writer Writer, apiKeys map[string]bool, logg
 		return nil, err
 	}
 
+	instanceID, err := random.HexString(8)
+	if err != nil {
+		return nil, err
+	}
+
 	rt := &Router{
-		Cache:   cache,
-		Writer:  writer,
-		Router:  mux.NewRouter(),
-		APIKeys: apiKeys,
-		log:     logger,
+		Cache:                  cache,
+		Writer:                 writer,
+		Router:                 mux.NewRouter(),
+		APIKeys:                apiKeys,
+		Capabilities:           capabilities,
+		Quotas:                 quotas,
+		IdempotencyTTL:         DefaultIdempotencyTTL,
+		IdempotencyLockTimeout: DefaultIdempotencyLockTimeout,
+		log:                    logger,
+		instanceID:             instanceID,
 	}
 
+	// routes match the escaped path so whitelisted origins can hold escaped slashes,
+	// UnescapeVarsHandler hands the variables unescaped to every handler
+	rt.Router.UseEncodedPath()
+
 	rt.Router.HandleFunc("/", rt.HealthCheck).Methods(http.MethodGet)
 	rt.Router.HandleFunc("/blockchain", rt.GetBlockchains).Methods(http.MethodGet)
 	rt.Router.HandleFunc("/blockchain", rt.CreateBlockchain).Methods(http.MethodPost)
 	rt.Router.HandleFunc("/blockchain/{id}", rt.GetBlockchain).Methods(http.MethodGet)
+	rt.Router.HandleFunc("/blockchain/{id}", rt.UpdateBlockchain).Methods(http.MethodPut)
+	rt.Router.HandleFunc("/blockchain/alias/{alias}", rt.GetBlockchainByAlias).Methods(http.MethodGet)
 	rt.Router.HandleFunc("/blockchain/{id}/activate", rt.ActivateBlockchain).Methods(http.MethodPost)
 	rt.Router.HandleFunc("/application", rt.GetApplications).Methods(http.MethodGet)
 	rt.Router.HandleFunc("/application", rt.CreateApplication).Methods(http.MethodPost)
 	rt.Router.HandleFunc("/application/limits", rt.GetApplicationsLimits).Methods(http.MethodGet)
+	rt.Router.HandleFunc("/application/batch", rt.GetApplicationsByIDs).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/application/public_key", rt.GetApplicationsByPublicKeys).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/application/bulk_update", rt.BulkUpdateApplications).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/application/public_key/{key}", rt.GetApplicationByPublicKey).Methods(http.MethodGet)
 	rt.Router.HandleFunc("/application/{id}", rt.GetApplication).Methods(http.MethodGet)
 	rt.Router.HandleFunc("/application/{id}", rt.UpdateApplication).Methods(http.MethodPut)
+	rt.Router.HandleFunc("/application/{id}", rt.PatchApplication).Methods(http.MethodPatch)
+	rt.Router.HandleFunc("/application/{id}/restore", rt.RestoreApplication).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/application/{id}/transfer", rt.TransferApplication).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/application/{id}/rotate_secret", rt.RotateApplicationSecret).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/application/{id}/rotate_aat", rt.RotateApplicationAAT).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/application/{id}/whitelist/{whitelist:contracts|methods}", rt.UpdateApplicationBlockchainWhitelist).Methods(http.MethodPost, http.MethodDelete)
+	rt.Router.HandleFunc("/application/{id}/whitelist/{whitelist:origins|user_agents|blockchains}/{value}", rt.UpdateApplicationWhitelist).Methods(http.MethodPost, http.MethodDelete)
 	rt.Router.HandleFunc("/application/first_date_surpassed", rt.UpdateFirstDateSurpassed).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/application/usage_state", rt.UpdateUsageStates).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/application/{id}/usage_state", rt.GetUsageState).Methods(http.MethodGet)
+	rt.Router.HandleFunc("/endpoint", rt.CreateEndpoint).Methods(http.MethodPost)
 	rt.Router.HandleFunc("/load_balancer", rt.GetLoadBalancers).Methods(http.MethodGet)
 	rt.Router.HandleFunc("/load_balancer", rt.CreateLoadBalancer).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/load_balancer/batch", rt.GetLoadBalancersByIDs).Methods(http.MethodPost)
 	rt.Router.HandleFunc("/load_balancer/{id}", rt.GetLoadBalancer).Methods(http.MethodGet)
 	rt.Router.HandleFunc("/load_balancer/{id}", rt.UpdateLoadBalancer).Methods(http.MethodPut)
+	rt.Router.HandleFunc("/load_balancer/{id}", rt.PatchLoadBalancer).Methods(http.MethodPatch)
+	rt.Router.HandleFunc("/load_balancer/{id}/restore", rt.RestoreLoadBalancer).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/load_balancer/{id}/transfer", rt.TransferLoadBalancer).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/user", rt.GetUsers).Methods(http.MethodGet)
+	rt.Router.HandleFunc("/user/{id}", rt.GetUser).Methods(http.MethodGet)
 	rt.Router.HandleFunc("/user/{id}/application", rt.GetApplicationByUserID).Methods(http.MethodGet)
 	rt.Router.HandleFunc("/user/{id}/load_balancer", rt.GetLoadBalancerByUserID).Methods(http.MethodGet)
+	rt.Router.HandleFunc("/user/{id}/transfer", rt.TransferUserEntities).Methods(http.MethodPost)
 	rt.Router.HandleFunc("/pay_plan", rt.GetPayPlans).Methods(http.MethodGet)
 	rt.Router.HandleFunc("/pay_plan/{type}", rt.GetPayPlan).Methods(http.MethodGet)
 	rt.Router.HandleFunc("/redirect", rt.CreateRedirect).Methods(http.MethodPost)
+	rt.Router.HandleFunc("/redirect/domain/{domain}", rt.GetRedirectByDomain).Methods(http.MethodGet)
+	rt.Router.HandleFunc("/changes", rt.GetChanges).Methods(http.MethodGet)
 
 	rt.Router.Use(rt.AuthorizationHandler)
+	rt.Router.Use(rt.UnescapeVarsHandler)
+	rt.Router.Use(rt.CompressionHandler)
+	rt.Router.Use(rt.IdempotencyHandler)
 
 	return rt, nil
 }
@@ -114,10 +215,119 @@ func (rt *Router) AuthorizationHandler(h http.Handler) http.Handler {
 			return
 		}
 
-		h.ServeHTTP(w, r)
+		h.ServeHTTP(w, rt.withCapabilities(r))
+	})
+}
+
+// UnescapeVarsHandler unescapes the route variables, as routes match the escaped path
+// the variables are taken from it and would reach the handlers still escaped otherwise
+func (rt *Router) UnescapeVarsHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		if len(vars) == 0 {
+			h.ServeHTTP(w, r)
+
+			return
+		}
+
+		unescapedVars := make(map[string]string, len(vars))
+
+		for name, value := range vars {
+			unescapedValue, err := url.PathUnescape(value)
+			if err != nil {
+				jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			unescapedVars[name] = unescapedValue
+		}
+
+		h.ServeHTTP(w, mux.SetURLVars(r, unescapedVars))
 	})
 }
 
+// notModified sets the ETag and Last-Modified headers for the given cache version and answers
+// with 304 when it matches the request If-None-Match header, the version must be read before
+// the cached entities so the tag sent is never newer than the body
+func (rt *Router) notModified(w http.ResponseWriter, r *http.Request, version cache.Version) bool {
+	etag := fmt.Sprintf(`"%s%s"`, rt.versionToken(version.Generation), representationVariant(r))
+
+	w.Header().Set("ETag", etag)
+	w.Header().Add("Vary", "Authorization")
+
+	if !version.UpdatedAt.IsZero() {
+		w.Header().Set("Last-Modified", version.UpdatedAt.UTC().Format(http.TimeFormat))
+	}
+
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+
+	return true
+}
+
+// representationVariant returns the suffix telling apart the representations of the same version, as
+// responses differ on the redaction of secrets, the projected fields and the content encoding
+func representationVariant(r *http.Request) string {
+	var variant strings.Builder
+
+	if !hasCapability(r, CapabilitySecretsRead) {
+		variant.WriteString("-redacted")
+	}
+
+	if fields := r.URL.Query().Get("fields"); fields != "" {
+		hash := fnv.New32a()
+		hash.Write([]byte(fields)) //nolint:errcheck
+
+		fmt.Fprintf(&variant, "-f%08x", hash.Sum32())
+	}
+
+	// mirrors CompressionHandler, which leaves HEAD responses uncompressed
+	if r.Method != http.MethodHead {
+		if encoding := negotiateEncoding(r.Header.Get("Accept-Encoding")); encoding != "" {
+			variant.WriteString("-" + encoding)
+		}
+	}
+
+	return variant.String()
+}
+
+// versionToken returns the opaque representation of a cache generation handed to clients
+func (rt *Router) versionToken(generation uint64) string {
+	return fmt.Sprintf("%s-%d", rt.instanceID, generation)
+}
+
+// parseVersionToken returns the cache generation of a token issued by versionToken, tokens
+// issued by another instance or before a restart are reported as not from this instance
+func (rt *Router) parseVersionToken(token string) (uint64, bool, error) {
+	instanceID, rawGeneration, found := strings.Cut(token, "-")
+	if !found {
+		return 0, false, errInvalidVersion
+	}
+
+	generation, err := strconv.ParseUint(rawGeneration, 10, 64)
+	if err != nil {
+		return 0, false, errInvalidVersion
+	}
+
+	return generation, instanceID == rt.instanceID, nil
+}
+
+// etagMatches reports whether any of the tags in the If-None-Match header weakly matches the etag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (rt *Router) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	_, err := w.Write([]byte("Pocket HTTP DB is up and running!"))
@@ -127,12 +337,24 @@ func (rt *Router) HealthCheck(w http.ResponseWriter, r *http.Request) {
 }
 
 func (rt *Router) GetApplications(w http.ResponseWriter, r *http.Request) {
-	jsonresponse.RespondWithJSON(w, http.StatusOK, rt.Cache.GetApplications())
+	version := rt.Cache.GetCollectionVersion(cache.CollectionApplications)
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	respondList(rt, w, r, rt.Cache.GetApplications())
 }
 
 // TODO - This Endpoint is DEPRECATED. Remove once Rate Limiter & Portal Workers are updated
 // to parse fields currently found in AppLimits from the /application endpoint instead.
 func (rt *Router) GetApplicationsLimits(w http.ResponseWriter, r *http.Request) {
+	version := rt.Cache.GetCollectionVersion(cache.CollectionApplications)
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
 	apps := rt.Cache.GetApplications()
 
 	var appsLimits []repository.AppLimits
@@ -155,12 +377,17 @@ func (rt *Router) GetApplicationsLimits(w http.ResponseWriter, r *http.Request)
 		appsLimits = append(appsLimits, appLimits)
 	}
 
-	jsonresponse.RespondWithJSON(w, http.StatusOK, appsLimits)
+	err := respondWithJSONList(w, http.StatusOK, appsLimits, encoder{})
+	if err != nil {
+		rt.logError(fmt.Errorf("GetApplicationsLimits write failed: %w", err))
+	}
 }
 
 func (rt *Router) GetApplication(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 
+	version := rt.Cache.GetEntityVersion(cache.CollectionApplications, vars["id"])
+
 	app := rt.Cache.GetApplication(vars["id"])
 
 	if app == nil {
@@ -168,7 +395,84 @@ func (rt *Router) GetApplication(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonresponse.RespondWithJSON(w, http.StatusOK, app)
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	rt.respond(w, r, http.StatusOK, app)
+}
+
+func (rt *Router) GetApplicationsByIDs(w http.ResponseWriter, r *http.Request) {
+	var input types.IDs
+
+	decoder := json.NewDecoder(r.Body)
+
+	err := decoder.Decode(&input)
+	if err != nil {
+		rt.logError(fmt.Errorf("GetApplicationsByIDs decode failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	if len(input.IDs) == 0 {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, errNoIDs.Error())
+		return
+	}
+
+	apps, missing := rt.Cache.GetApplicationsByIDs(input.IDs)
+
+	rt.respond(w, r, http.StatusOK, types.BatchApplications{
+		Applications: apps,
+		Missing:      missing,
+	})
+}
+
+func (rt *Router) GetApplicationByPublicKey(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	version := rt.Cache.GetCollectionVersion(cache.CollectionApplications)
+
+	app := rt.Cache.GetApplicationByPublicKey(vars["key"])
+
+	if app == nil {
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errApplicationNotFound.Error())
+		return
+	}
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	rt.respond(w, r, http.StatusOK, app)
+}
+
+func (rt *Router) GetApplicationsByPublicKeys(w http.ResponseWriter, r *http.Request) {
+	var input types.PublicKeys
+
+	decoder := json.NewDecoder(r.Body)
+
+	err := decoder.Decode(&input)
+	if err != nil {
+		rt.logError(fmt.Errorf("GetApplicationsByPublicKeys decode failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	if len(input.PublicKeys) == 0 {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, errNoPublicKeys.Error())
+		return
+	}
+
+	apps, missing := rt.Cache.GetApplicationsByPublicKeys(input.PublicKeys)
+
+	rt.respond(w, r, http.StatusOK, types.BatchApplications{
+		Applications: apps,
+		Missing:      missing,
+	})
 }
 
 func (rt *Router) CreateApplication(w http.ResponseWriter, r *http.Request) {
@@ -183,6 +487,25 @@ func (rt *Router) CreateApplication(w http.ResponseWriter, r *http.Request) {
 	}
 
 	defer r.Body.Close()
+
+	err = types.NormalizeWhitelists(&app.GatewaySettings)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !rt.checkPayPlan(w, app.Limit.PayPlan.Type) {
+		return
+	}
+
+	unlockQuotas, ok := rt.enforceQuotas(w, r, app.UserID, quotaChange{
+		applications: []repository.PayPlanType{app.Limit.PayPlan.Type},
+	})
+	if !ok {
+		return
+	}
+	defer unlockQuotas()
+
 	fullApp, err := rt.Writer.WriteApplication(&app)
 	if err != nil {
 		rt.logError(fmt.Errorf("WriteApplication in CreateApplication failed: %w", errApplicationNotFound))
@@ -190,12 +513,9 @@ func (rt *Router) CreateApplication(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if fullApp.Limit.PayPlan.Type != repository.Enterprise {
-		newPlan := rt.Cache.GetPayPlan(fullApp.Limit.PayPlan.Type)
-		fullApp.Limit.PayPlan.Limit = newPlan.Limit
-	}
+	rt.setPayPlanLimit(&fullApp.Limit)
 
-	jsonresponse.RespondWithJSON(w, http.StatusOK, fullApp)
+	rt.respond(w, r, http.StatusOK, fullApp)
 }
 
 func (rt *Router) UpdateApplication(w http.ResponseWriter, r *http.Request) {
@@ -220,8 +540,30 @@ func (rt *Router) UpdateApplication(w http.ResponseWriter, r *http.Request) {
 
 	defer r.Body.Close()
 
+	if updateInput.GatewaySettings != nil {
+		err = types.NormalizeWhitelists(updateInput.GatewaySettings)
+		if err != nil {
+			jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if updateInput.Limit != nil && !rt.checkPayPlan(w, updateInput.Limit.PayPlan.Type) {
+		return
+	}
+
+	ifUpdatedAt, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	var updatedAt time.Time
+
 	if updateInput.Remove {
-		err = rt.Writer.RemoveApplication(vars["id"])
+		updatedAt, err = rt.Writer.RemoveApplication(vars["id"], ifUpdatedAt)
+		if preconditionFailed(w, err) {
+			return
+		}
 		if err != nil {
 			rt.logError(fmt.Errorf("RemoveApplication in UpdateApplication failed: %w", err))
 			jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
@@ -230,151 +572,839 @@ func (rt *Router) UpdateApplication(w http.ResponseWriter, r *http.Request) {
 
 		app.Status = repository.AwaitingGracePeriod
 	} else {
-		err = rt.Writer.UpdateApplication(vars["id"], &updateInput)
-		if err != nil {
-			jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		updatedApp := *app
+		rt.applyApplicationUpdate(&updatedApp, &updateInput)
+
+		unlockQuotas, ok := rt.enforceQuotas(w, r, app.UserID, applicationQuotaChange(app, &updatedApp))
+		if !ok {
 			return
 		}
+		defer unlockQuotas()
 
-		if updateInput.Name != "" {
-			app.Name = updateInput.Name
-		}
-		if updateInput.Status != "" {
-			app.Status = updateInput.Status
-		}
-		if !updateInput.FirstDateSurpassed.IsZero() {
-			app.FirstDateSurpassed = updateInput.FirstDateSurpassed
-		}
-		if updateInput.Limit != nil {
-			if updateInput.Limit.PayPlan.Type != repository.Enterprise {
-				newPlan := rt.Cache.GetPayPlan(updateInput.Limit.PayPlan.Type)
-				updateInput.Limit.PayPlan.Limit = newPlan.Limit
-			}
-			app.Limit = *updateInput.Limit
-		}
-		if updateInput.GatewaySettings != nil {
-			app.GatewaySettings = *updateInput.GatewaySettings
+		updatedAt, err = rt.Writer.UpdateApplication(vars["id"], &updateInput, ifUpdatedAt)
+		if preconditionFailed(w, err) {
+			return
 		}
-		if updateInput.NotificationSettings != nil {
-			app.NotificationSettings = *updateInput.NotificationSettings
+		if err != nil {
+			jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+			return
 		}
+
+		rt.applyApplicationUpdate(app, &updateInput)
 	}
 
-	jsonresponse.RespondWithJSON(w, http.StatusOK, app)
-}
+	app.UpdatedAt = updatedAt
 
-func (rt *Router) UpdateFirstDateSurpassed(w http.ResponseWriter, r *http.Request) {
-	var updateInput repository.UpdateFirstDateSurpassed
+	rt.Cache.BumpVersion(cache.CollectionApplications, app.ID)
 
-	decoder := json.NewDecoder(r.Body)
+	rt.respond(w, r, http.StatusOK, app)
+}
 
-	err := decoder.Decode(&updateInput)
-	if err != nil {
-		rt.logError(fmt.Errorf("UpdateFirstDateSurpassed decode failed: %w", err))
-		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
-		return
+// applyApplicationUpdate sets the fields of the update on the application as UpdateApplication saves them
+func (rt *Router) applyApplicationUpdate(app *repository.Application, update *repository.UpdateApplication) {
+	if update.Name != "" {
+		app.Name = update.Name
 	}
-
-	defer r.Body.Close()
-
-	if len(updateInput.ApplicationIDs) == 0 {
-		jsonresponse.RespondWithError(w, http.StatusBadRequest, "no application IDs on input")
-		return
+	if update.Status != "" {
+		app.Status = update.Status
+	}
+	if !update.FirstDateSurpassed.IsZero() {
+		app.FirstDateSurpassed = update.FirstDateSurpassed
 	}
+	if update.Limit != nil {
+		rt.setPayPlanLimit(update.Limit)
+		app.Limit = *update.Limit
+	}
+	if update.GatewaySettings != nil {
+		app.GatewaySettings = *update.GatewaySettings
+	}
+	if update.NotificationSettings != nil {
+		app.NotificationSettings = *update.NotificationSettings
+	}
+}
 
-	var appsToUpdate []*repository.Application
+// checkPayPlan responds with an unprocessable entity error when the pay plan is neither enterprise nor
+// one of the pay plans in the cache, as the application limit could not be set from it, applications
+// without a pay plan are left as they are
+func (rt *Router) checkPayPlan(w http.ResponseWriter, planType repository.PayPlanType) bool {
+	if planType == "" || planType == repository.Enterprise || rt.Cache.GetPayPlan(planType) != nil {
+		return true
+	}
 
-	for _, appID := range updateInput.ApplicationIDs {
-		app := rt.Cache.GetApplication(appID)
-		if app == nil {
-			jsonresponse.RespondWithError(w, http.StatusNotFound, fmt.Sprintf("%s not found", appID))
-			return
-		}
+	rt.logError(fmt.Errorf("GetPayPlan %q failed: %w", planType, errNoPayFound))
+	jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, errNoPayFound.Error())
 
-		appsToUpdate = append(appsToUpdate, app)
-	}
+	return false
+}
 
-	err = rt.Writer.UpdateFirstDateSurpassed(&updateInput)
-	if err != nil {
-		rt.logError(fmt.Errorf("UpdateFirstDateSurpassed failed: %W", err))
-		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+// setPayPlanLimit sets the limit of the pay plan in the cache on the application limit, enterprise
+// applications keep their custom limit
+func (rt *Router) setPayPlanLimit(limit *repository.AppLimit) {
+	if limit.PayPlan.Type == repository.Enterprise {
 		return
 	}
 
-	for _, app := range appsToUpdate {
-		app.FirstDateSurpassed = updateInput.FirstDateSurpassed
+	plan := rt.Cache.GetPayPlan(limit.PayPlan.Type)
+	if plan != nil {
+		limit.PayPlan.Limit = plan.Limit
 	}
-
-	jsonresponse.RespondWithJSON(w, http.StatusOK, appsToUpdate)
 }
 
-func (rt *Router) GetApplicationByUserID(w http.ResponseWriter, r *http.Request) {
+// PatchApplication applies a JSON merge patch to the application, unlike UpdateApplication null
+// members clear the fields, the cache of every instance is updated by the database notification
+func (rt *Router) PatchApplication(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 
-	apps := rt.Cache.GetApplicationsByUserID(vars["id"])
-
-	if len(apps) == 0 {
-		rt.logError(fmt.Errorf("GetLoadBalancerByUserID failed: %w", errApplicationNotFound))
+	app := rt.Cache.GetApplication(vars["id"])
+	if app == nil {
+		rt.logError(fmt.Errorf("GetApplication in PatchApplication failed: %w", errApplicationNotFound))
 		jsonresponse.RespondWithError(w, http.StatusNotFound, errApplicationNotFound.Error())
 		return
 	}
 
-	jsonresponse.RespondWithJSON(w, http.StatusOK, apps)
-}
+	var patchedApp repository.Application
 
-func (rt *Router) GetLoadBalancerByUserID(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
+	if !rt.decodeMergePatch(w, r, app, patchableApplicationFields, &patchedApp) {
+		return
+	}
 
-	lbs := rt.Cache.GetLoadBalancersByUserID(vars["id"])
+	validation := repository.UpdateApplication{Status: patchedApp.Status, Limit: &patchedApp.Limit}
 
-	if len(lbs) == 0 {
-		rt.logError(fmt.Errorf("GetLoadBalancerByUserID failed: %w", errBalancerNotFound))
-		jsonresponse.RespondWithError(w, http.StatusNotFound, errBalancerNotFound.Error())
+	err := validation.Validate()
+	if err == nil {
+		err = types.NormalizeWhitelists(&patchedApp.GatewaySettings)
+	}
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
 		return
 	}
 
-	jsonresponse.RespondWithJSON(w, http.StatusOK, lbs)
-}
+	if !rt.checkPayPlan(w, patchedApp.Limit.PayPlan.Type) {
+		return
+	}
 
-func (rt *Router) GetBlockchain(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
+	ifUpdatedAt, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
 
-	blockchain := rt.Cache.GetBlockchain(vars["id"])
+	unlockQuotas, ok := rt.enforceQuotas(w, r, app.UserID, applicationQuotaChange(app, &patchedApp))
+	if !ok {
+		return
+	}
+	defer unlockQuotas()
 
-	if blockchain == nil {
-		rt.logError(fmt.Errorf("GetBlockchain failed: %w", errBlockchainNotFound))
-		jsonresponse.RespondWithError(w, http.StatusNotFound, errBlockchainNotFound.Error())
+	patchedApp.UpdatedAt, err = rt.Writer.PatchApplication(&patchedApp, ifUpdatedAt)
+	if preconditionFailed(w, err) {
+		return
+	}
+	if err != nil {
+		rt.logError(fmt.Errorf("PatchApplication failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
 		return
 	}
 
-	jsonresponse.RespondWithJSON(w, http.StatusOK, blockchain)
+	rt.setPayPlanLimit(&patchedApp.Limit)
+
+	rt.respond(w, r, http.StatusOK, &patchedApp)
 }
 
-func (rt *Router) ActivateBlockchain(w http.ResponseWriter, r *http.Request) {
+// RestoreApplication reverts the removal of an application still awaiting its grace period,
+// the cache of every instance is updated by the database notification
+func (rt *Router) RestoreApplication(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	blockchainID := vars["id"]
-
-	var active bool
 
-	decoder := json.NewDecoder(r.Body)
+	app := rt.Cache.GetApplication(vars["id"])
+	if app == nil {
+		rt.logError(fmt.Errorf("GetApplication in RestoreApplication failed: %w", errApplicationNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errApplicationNotFound.Error())
+		return
+	}
 
-	err := decoder.Decode(&active)
-	if err != nil {
-		rt.logError(fmt.Errorf("ActivateBlockchain decode failed: %w", err))
-		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+	if app.Status != repository.AwaitingGracePeriod {
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, errApplicationNotRemoved.Error())
 		return
 	}
 
-	defer r.Body.Close()
+	unlockQuotas, ok := rt.enforceQuotas(w, r, app.UserID, quotaChange{
+		applications: []repository.PayPlanType{app.Limit.PayPlan.Type},
+	})
+	if !ok {
+		return
+	}
+	defer unlockQuotas()
 
-	err = rt.Writer.ActivateBlockchain(blockchainID, active)
+	removal, err := rt.Writer.RestoreApplication(app.ID)
 	if err != nil {
-		rt.logError(fmt.Errorf("ActivateBlockchain failed: %w", err))
-		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		rt.logError(fmt.Errorf("RestoreApplication failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
 		return
 	}
 
-	jsonresponse.RespondWithJSON(w, http.StatusOK, active)
+	restoredApp := *app
+	restoredApp.Status = removal.PreviousStatus
+	if removal.UserID != "" {
+		restoredApp.UserID = removal.UserID
+	}
+
+	rt.respond(w, r, http.StatusOK, &restoredApp)
+}
+
+// RotateApplicationSecret replaces the gateway secret key of the application with a new random one, only API keys
+// able to read secrets can rotate them, the cache of every instance is updated by the database notification
+func (rt *Router) RotateApplicationSecret(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if !hasCapability(r, CapabilitySecretsRead) {
+		jsonresponse.RespondWithError(w, http.StatusForbidden, errSecretsReadRequired.Error())
+		return
+	}
+
+	app := rt.Cache.GetApplication(vars["id"])
+	if app == nil {
+		rt.logError(fmt.Errorf("GetApplication in RotateApplicationSecret failed: %w", errApplicationNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errApplicationNotFound.Error())
+		return
+	}
+
+	secretKey, err := random.HexString(secretKeyLength)
+	if err != nil {
+		rt.logError(fmt.Errorf("RotateApplicationSecret generate secret failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = rt.Writer.UpdateSecretKey(app.ID, secretKey)
+	if err != nil {
+		rt.logError(fmt.Errorf("UpdateSecretKey in RotateApplicationSecret failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	rotatedApp := *app
+	rotatedApp.GatewaySettings.SecretKey = secretKey
+
+	rt.respond(w, r, http.StatusOK, &rotatedApp)
+}
+
+// RotateApplicationAAT reissues the gateway AAT of the application signed with its current key pair, which must
+// stay the staked one, for a new client public key or version, only API keys able to read secrets can reissue it,
+// the cache of every instance is updated by the database notification
+func (rt *Router) RotateApplicationAAT(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if !hasCapability(r, CapabilitySecretsRead) {
+		jsonresponse.RespondWithError(w, http.StatusForbidden, errSecretsReadRequired.Error())
+		return
+	}
+
+	app := rt.Cache.GetApplication(vars["id"])
+	if app == nil {
+		rt.logError(fmt.Errorf("GetApplication in RotateApplicationAAT failed: %w", errApplicationNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errApplicationNotFound.Error())
+		return
+	}
+
+	var rotateInput types.RotateAAT
+
+	// the body is optional, without it the AAT is reissued for the same client and version
+	if r.Body != nil {
+		err := json.NewDecoder(r.Body).Decode(&rotateInput)
+		if err != nil && !errors.Is(err, io.EOF) {
+			jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		defer r.Body.Close()
+	}
+
+	clientPublicKey := app.GatewayAAT.ClientPublicKey
+	if rotateInput.ClientPublicKey != "" {
+		clientPublicKey = rotateInput.ClientPublicKey
+	}
+
+	version := app.GatewayAAT.Version
+	if rotateInput.Version != "" {
+		version = rotateInput.Version
+	}
+
+	newAAT, err := aat.Sign(app.GatewayAAT.PrivateKey, clientPublicKey, version)
+	if errors.Is(err, aat.ErrMissingPrivateKey) || errors.Is(err, aat.ErrInvalidPrivateKey) ||
+		errors.Is(err, aat.ErrMissingClientPublicKey) {
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err != nil {
+		rt.logError(fmt.Errorf("RotateApplicationAAT sign aat failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// the signing key must be the staked one the relays are checked against
+	if app.GatewayAAT.ApplicationPublicKey != "" && !strings.EqualFold(app.GatewayAAT.ApplicationPublicKey, newAAT.ApplicationPublicKey) {
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, errAATKeyMismatch.Error())
+		return
+	}
+
+	err = rt.Writer.UpdateGatewayAAT(app.ID, newAAT)
+	if err != nil {
+		rt.logError(fmt.Errorf("UpdateGatewayAAT in RotateApplicationAAT failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	rotatedApp := *app
+	rotatedApp.GatewayAAT = *newAAT
+
+	rt.respond(w, r, http.StatusOK, &rotatedApp)
+}
+
+// UpdateApplicationWhitelist adds the value on the path to the origins, user agents or blockchains whitelist of
+// the application, or removes it on DELETE, the value must be path escaped and whitelisted blockchains must exist
+func (rt *Router) UpdateApplicationWhitelist(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	app := rt.Cache.GetApplication(vars["id"])
+	if app == nil {
+		rt.logError(fmt.Errorf("GetApplication in UpdateApplicationWhitelist failed: %w", errApplicationNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errApplicationNotFound.Error())
+		return
+	}
+
+	value := vars["value"]
+
+	change := &types.WhitelistChange{
+		Type:   types.WhitelistType(vars["whitelist"]),
+		Values: []string{value},
+		Remove: r.Method == http.MethodDelete,
+	}
+
+	if change.Type == types.WhitelistBlockchains && !change.Remove && rt.Cache.GetBlockchain(value) == nil {
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, errBlockchainNotFound.Error())
+		return
+	}
+
+	rt.updateWhitelist(w, r, app, change)
+}
+
+// UpdateApplicationBlockchainWhitelist adds the contracts or methods on the body to the whitelist of the
+// application for their blockchain, or removes them on DELETE, removing none removes all of the blockchain
+func (rt *Router) UpdateApplicationBlockchainWhitelist(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	app := rt.Cache.GetApplication(vars["id"])
+	if app == nil {
+		rt.logError(fmt.Errorf("GetApplication in UpdateApplicationBlockchainWhitelist failed: %w", errApplicationNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errApplicationNotFound.Error())
+		return
+	}
+
+	change := &types.WhitelistChange{
+		Type:   types.WhitelistType(vars["whitelist"]),
+		Remove: r.Method == http.MethodDelete,
+	}
+
+	decoder := json.NewDecoder(r.Body)
+
+	var err error
+
+	if change.Type == types.WhitelistContracts {
+		var contract repository.WhitelistContract
+
+		err = decoder.Decode(&contract)
+		change.BlockchainID, change.Values = contract.BlockchainID, contract.Contracts
+	} else {
+		var method repository.WhitelistMethod
+
+		err = decoder.Decode(&method)
+		change.BlockchainID, change.Values = method.BlockchainID, method.Methods
+	}
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	if !change.Remove && rt.Cache.GetBlockchain(change.BlockchainID) == nil {
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, errBlockchainNotFound.Error())
+		return
+	}
+
+	rt.updateWhitelist(w, r, app, change)
+}
+
+// updateWhitelist writes the whitelist change and responds with a copy of the application holding the
+// new whitelists, the cache of every instance is updated by the database notification
+func (rt *Router) updateWhitelist(w http.ResponseWriter, r *http.Request, app *repository.Application, change *types.WhitelistChange) {
+	err := change.Validate()
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	settings, updatedAt, err := rt.Writer.UpdateWhitelist(app.ID, change)
+	if err != nil {
+		rt.logError(fmt.Errorf("UpdateWhitelist failed: %w", err))
+		jsonresponse.RespondWithError(w, whitelistErrorCode(err), err.Error())
+		return
+	}
+
+	updatedApp := *app
+	updatedApp.UpdatedAt = updatedAt
+	updatedApp.GatewaySettings.WhitelistOrigins = settings.WhitelistOrigins
+	updatedApp.GatewaySettings.WhitelistUserAgents = settings.WhitelistUserAgents
+	updatedApp.GatewaySettings.WhitelistBlockchains = settings.WhitelistBlockchains
+	updatedApp.GatewaySettings.WhitelistContracts = settings.WhitelistContracts
+	updatedApp.GatewaySettings.WhitelistMethods = settings.WhitelistMethods
+
+	rt.respond(w, r, http.StatusOK, &updatedApp)
+}
+
+// whitelistErrorCode returns the status of a failed whitelist update, changes the stored whitelists
+// cannot take are unprocessable while any other error comes from the database
+func whitelistErrorCode(err error) int {
+	switch {
+	case errors.Is(err, types.ErrApplicationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrInvalidWhitelistType), errors.Is(err, types.ErrNoWhitelistValues),
+		errors.Is(err, types.ErrMissingWhitelistBlockchain), errors.Is(err, types.ErrInvalidContract),
+		errors.Is(err, types.ErrInvalidContractChecksum), errors.Is(err, types.ErrInvalidMethod):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// UpdateFirstDateSurpassed sets the same first date surpassed on every application, failing the whole
+// batch when any of them is missing, UpdateUsageStates supersedes it reporting the result of each one
+func (rt *Router) UpdateFirstDateSurpassed(w http.ResponseWriter, r *http.Request) {
+	var updateInput repository.UpdateFirstDateSurpassed
+
+	decoder := json.NewDecoder(r.Body)
+
+	err := decoder.Decode(&updateInput)
+	if err != nil {
+		rt.logError(fmt.Errorf("UpdateFirstDateSurpassed decode failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	if len(updateInput.ApplicationIDs) == 0 {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, "no application IDs on input")
+		return
+	}
+
+	var appsToUpdate []*repository.Application
+
+	for _, appID := range updateInput.ApplicationIDs {
+		app := rt.Cache.GetApplication(appID)
+		if app == nil {
+			jsonresponse.RespondWithError(w, http.StatusNotFound, fmt.Sprintf("%s not found", appID))
+			return
+		}
+
+		appsToUpdate = append(appsToUpdate, app)
+	}
+
+	err = rt.Writer.UpdateFirstDateSurpassed(&updateInput)
+	if err != nil {
+		rt.logError(fmt.Errorf("UpdateFirstDateSurpassed failed: %W", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	for _, app := range appsToUpdate {
+		app.FirstDateSurpassed = updateInput.FirstDateSurpassed
+	}
+
+	rt.Cache.BumpVersion(cache.CollectionApplications, updateInput.ApplicationIDs...)
+
+	rt.respond(w, r, http.StatusOK, appsToUpdate)
+}
+
+// BulkUpdateApplications applies the same update to the applications selected by ID or by filter in a single
+// transaction, reporting the fields changed on each, on a dry run the changes are only reported
+func (rt *Router) BulkUpdateApplications(w http.ResponseWriter, r *http.Request) {
+	var bulkUpdate types.BulkUpdateApplications
+
+	decoder := json.NewDecoder(r.Body)
+
+	err := decoder.Decode(&bulkUpdate)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	err = bulkUpdate.Validate()
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if bulkUpdate.Update.Limit != nil && !rt.checkPayPlan(w, bulkUpdate.Update.Limit.PayPlan.Type) {
+		return
+	}
+
+	result := types.BulkUpdateResult{
+		DryRun:    bulkUpdate.DryRun,
+		Updated:   []types.BulkUpdatedApplication{},
+		Unchanged: []string{},
+		Missing:   []string{},
+	}
+
+	var apps []*repository.Application
+
+	if bulkUpdate.Filter != nil {
+		for _, app := range rt.Cache.GetApplications() {
+			if bulkUpdate.Filter.Match(app) {
+				apps = append(apps, app)
+			}
+		}
+	} else {
+		apps, result.Missing = rt.Cache.GetApplicationsByIDs(uniqueIDs(bulkUpdate.ApplicationIDs))
+	}
+
+	var updatedApps []*repository.Application
+
+	quotaChanges := make(map[string]quotaChange)
+
+	for _, app := range apps {
+		update := bulkUpdate.Update
+		if update.GatewaySettings != nil {
+			settings := app.GatewaySettings
+			settings.SecretKeyRequired = bulkUpdate.Update.GatewaySettings.SecretKeyRequired
+			update.GatewaySettings = &settings
+		}
+
+		updatedApp := *app
+		rt.applyApplicationUpdate(&updatedApp, &update)
+
+		changes, err := applicationChanges(app, &updatedApp)
+		if err != nil {
+			rt.logError(fmt.Errorf("applicationChanges in BulkUpdateApplications failed: %w", err))
+			jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if len(changes) == 0 {
+			result.Unchanged = append(result.Unchanged, app.ID)
+			continue
+		}
+
+		result.Updated = append(result.Updated, types.BulkUpdatedApplication{
+			ApplicationID: app.ID,
+			Changes:       changes,
+		})
+		updatedApps = append(updatedApps, &updatedApp)
+		quotaChanges[app.UserID] = quotaChanges[app.UserID].add(applicationQuotaChange(app, &updatedApp))
+	}
+
+	if !bulkUpdate.DryRun && len(updatedApps) > 0 {
+		unlockQuotas, ok := rt.enforceUsersQuotas(w, r, quotaChanges)
+		if !ok {
+			return
+		}
+		defer unlockQuotas()
+
+		err = rt.Writer.PatchApplications(updatedApps)
+		if errors.Is(err, types.ErrApplicationChanged) {
+			jsonresponse.RespondWithError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if err != nil {
+			rt.logError(fmt.Errorf("PatchApplications in BulkUpdateApplications failed: %w", err))
+			jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+	}
+
+	rt.respond(w, r, http.StatusOK, result)
+}
+
+// UpdateUsageStates applies a batch of limit related state updates, each one is answered with its own status
+// so the valid ones are saved, in a single transaction, even when others are not valid or their application is missing
+func (rt *Router) UpdateUsageStates(w http.ResponseWriter, r *http.Request) {
+	var batch types.UsageStateUpdates
+
+	decoder := json.NewDecoder(r.Body)
+
+	err := decoder.Decode(&batch)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	if len(batch.Updates) == 0 {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, "no usage state updates on input")
+		return
+	}
+
+	results := make([]types.UsageStateResult, len(batch.Updates))
+
+	var updates []*types.UsageStateUpdate
+	var resultIndexes []int
+
+	for i, update := range batch.Updates {
+		err = update.Validate()
+		if update != nil {
+			results[i].ApplicationID = update.ApplicationID
+		}
+		if err != nil {
+			results[i].Status = http.StatusBadRequest
+			results[i].Error = err.Error()
+			continue
+		}
+
+		if rt.Cache.GetApplication(update.ApplicationID) == nil {
+			results[i].Status = http.StatusNotFound
+			results[i].Error = errApplicationNotFound.Error()
+			continue
+		}
+
+		updates = append(updates, update)
+		resultIndexes = append(resultIndexes, i)
+	}
+
+	if len(updates) > 0 {
+		states, errs, err := rt.Writer.UpdateUsageStates(updates)
+		if err != nil {
+			rt.logError(fmt.Errorf("UpdateUsageStates failed: %w", err))
+			jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		for j, state := range states {
+			i := resultIndexes[j]
+
+			// removed from the database after the cache was read
+			if errors.Is(errs[j], types.ErrApplicationNotFound) {
+				results[i].Status = http.StatusNotFound
+				results[i].Error = errApplicationNotFound.Error()
+				continue
+			}
+
+			if errs[j] != nil {
+				rt.logError(fmt.Errorf("UpdateUsageStates of application %s failed: %w", results[i].ApplicationID, errs[j]))
+				results[i].Status = http.StatusInternalServerError
+				results[i].Error = errs[j].Error()
+				continue
+			}
+
+			results[i].Status = http.StatusOK
+			results[i].UsageState = state
+		}
+	}
+
+	rt.respond(w, r, http.StatusOK, results)
+}
+
+// GetUsageState returns the limit related state of the application, including
+// the thresholds reached which are only stored in the database
+func (rt *Router) GetUsageState(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if rt.Cache.GetApplication(vars["id"]) == nil {
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errApplicationNotFound.Error())
+		return
+	}
+
+	state, err := rt.Writer.ReadUsageState(vars["id"])
+	if err != nil {
+		rt.logError(fmt.Errorf("ReadUsageState in GetUsageState failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	rt.respond(w, r, http.StatusOK, state)
+}
+
+func (rt *Router) GetApplicationByUserID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	version := rt.Cache.GetCollectionVersion(cache.CollectionApplications)
+
+	apps := rt.Cache.GetApplicationsByUserID(vars["id"])
+
+	if len(apps) == 0 {
+		rt.logError(fmt.Errorf("GetLoadBalancerByUserID failed: %w", errApplicationNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errApplicationNotFound.Error())
+		return
+	}
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	respondList(rt, w, r, apps)
+}
+
+func (rt *Router) GetLoadBalancerByUserID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	version := rt.Cache.GetCollectionVersion(cache.CollectionLoadBalancers, cache.CollectionApplications)
+
+	lbs := rt.Cache.GetLoadBalancersByUserID(vars["id"])
+
+	if len(lbs) == 0 {
+		rt.logError(fmt.Errorf("GetLoadBalancerByUserID failed: %w", errBalancerNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errBalancerNotFound.Error())
+		return
+	}
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	respondList(rt, w, r, lbs)
+}
+
+// summarizeUser returns the stats of the applications and load balancers owned by the user
+func summarizeUser(userID string, apps []*repository.Application, lbs []*repository.LoadBalancer) types.UserSummary {
+	summary := types.UserSummary{
+		UserID:                userID,
+		ApplicationsCount:     len(apps),
+		LoadBalancersCount:    len(lbs),
+		ApplicationsByStatus:  make(map[repository.AppStatus]int),
+		ApplicationsByPayPlan: make(map[repository.PayPlanType]int),
+	}
+
+	for _, app := range apps {
+		summary.ApplicationsByStatus[app.Status]++
+		summary.ApplicationsByPayPlan[app.Limit.PayPlan.Type]++
+
+		if app.Status != repository.AwaitingGracePeriod {
+			summary.TotalDailyLimit += app.DailyLimit()
+		}
+	}
+
+	return summary
+}
+
+// GetUsers returns the summary of every user owning applications or load balancers
+func (rt *Router) GetUsers(w http.ResponseWriter, r *http.Request) {
+	version := rt.Cache.GetCollectionVersion(cache.CollectionApplications, cache.CollectionLoadBalancers)
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	userIDs := rt.Cache.GetUserIDs()
+
+	summaries := make([]types.UserSummary, 0, len(userIDs))
+	for _, userID := range userIDs {
+		summaries = append(summaries, summarizeUser(userID,
+			rt.Cache.GetApplicationsByUserID(userID), rt.Cache.GetLoadBalancersByUserID(userID)))
+	}
+
+	respondList(rt, w, r, summaries)
+}
+
+// GetUser returns every application and load balancer owned by the user along with their summary
+func (rt *Router) GetUser(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	version := rt.Cache.GetCollectionVersion(cache.CollectionApplications, cache.CollectionLoadBalancers)
+
+	apps := rt.Cache.GetApplicationsByUserID(vars["id"])
+	lbs := rt.Cache.GetLoadBalancersByUserID(vars["id"])
+
+	if len(apps) == 0 && len(lbs) == 0 {
+		rt.logError(fmt.Errorf("GetUser failed: %w", errUserNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errUserNotFound.Error())
+		return
+	}
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	if apps == nil {
+		apps = []*repository.Application{}
+	}
+
+	if lbs == nil {
+		lbs = []*repository.LoadBalancer{}
+	}
+
+	rt.respond(w, r, http.StatusOK, types.User{
+		Summary:       summarizeUser(vars["id"], apps, lbs),
+		Applications:  apps,
+		LoadBalancers: lbs,
+	})
+}
+
+func (rt *Router) GetBlockchain(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	version := rt.Cache.GetEntityVersion(cache.CollectionBlockchains, vars["id"])
+
+	blockchain := rt.Cache.GetBlockchain(vars["id"])
+
+	if blockchain == nil {
+		rt.logError(fmt.Errorf("GetBlockchain failed: %w", errBlockchainNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errBlockchainNotFound.Error())
+		return
+	}
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	rt.respond(w, r, http.StatusOK, blockchain)
+}
+
+func (rt *Router) GetBlockchainByAlias(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	version := rt.Cache.GetCollectionVersion(cache.CollectionBlockchains)
+
+	blockchain := rt.Cache.GetBlockchainByAlias(vars["alias"])
+
+	if blockchain == nil {
+		rt.logError(fmt.Errorf("GetBlockchainByAlias failed: %w", errBlockchainNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errBlockchainNotFound.Error())
+		return
+	}
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	rt.respond(w, r, http.StatusOK, blockchain)
+}
+
+func (rt *Router) ActivateBlockchain(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	blockchainID := vars["id"]
+
+	var active bool
+
+	decoder := json.NewDecoder(r.Body)
+
+	err := decoder.Decode(&active)
+	if err != nil {
+		rt.logError(fmt.Errorf("ActivateBlockchain decode failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	err = rt.Writer.ActivateBlockchain(blockchainID, active)
+	if err != nil {
+		rt.logError(fmt.Errorf("ActivateBlockchain failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	rt.respond(w, r, http.StatusOK, active)
 }
 
 func (rt *Router) CreateBlockchain(w http.ResponseWriter, r *http.Request) {
@@ -382,130 +1412,506 @@ func (rt *Router) CreateBlockchain(w http.ResponseWriter, r *http.Request) {
 
 	decoder := json.NewDecoder(r.Body)
 
-	err := decoder.Decode(&blockchain)
+	err := decoder.Decode(&blockchain)
+	if err != nil {
+		rt.logError(fmt.Errorf("CreateBlockchain decode failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	if !rt.checkBlockchainAliases(w, blockchain.ID, blockchain.BlockchainAliases) {
+		return
+	}
+
+	fullBlockchain, err := rt.Writer.WriteBlockchain(&blockchain)
+	if err != nil {
+		rt.logError(fmt.Errorf("WriteBlockchain in CreateBlockchain failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	rt.respond(w, r, http.StatusOK, fullBlockchain)
+}
+
+// checkBlockchainAliases responds and returns false when an alias is repeated or already used by another
+// blockchain, aliases are case insensitive so they are compared lower cased as the cache indexes them
+func (rt *Router) checkBlockchainAliases(w http.ResponseWriter, id string, aliases []string) bool {
+	seen := make(map[string]bool, len(aliases))
+
+	for _, alias := range aliases {
+		if seen[strings.ToLower(alias)] {
+			jsonresponse.RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("%s: %s", errAliasRepeated.Error(), alias))
+			return false
+		}
+
+		seen[strings.ToLower(alias)] = true
+
+		aliasOwner := rt.Cache.GetBlockchainByAlias(alias)
+		if aliasOwner != nil && aliasOwner.ID != id {
+			jsonresponse.RespondWithError(w, http.StatusConflict, fmt.Sprintf("%s: %s", errAliasInUse.Error(), alias))
+			return false
+		}
+	}
+
+	return true
+}
+
+func (rt *Router) UpdateBlockchain(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	blockchain := rt.Cache.GetBlockchain(vars["id"])
+	if blockchain == nil {
+		rt.logError(fmt.Errorf("GetBlockchain in UpdateBlockchain failed: %w", errBlockchainNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errBlockchainNotFound.Error())
+		return
+	}
+
+	var updateInput types.UpdateBlockchain
+
+	decoder := json.NewDecoder(r.Body)
+
+	err := decoder.Decode(&updateInput)
+	if err != nil {
+		rt.logError(fmt.Errorf("UpdateBlockchain decode failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	if !rt.checkBlockchainAliases(w, blockchain.ID, updateInput.BlockchainAliases) {
+		return
+	}
+
+	err = rt.Writer.UpdateBlockchain(vars["id"], &updateInput)
+	if err != nil {
+		rt.logError(fmt.Errorf("UpdateBlockchain failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	// responses are copies, the cache of every instance is updated by the database notification
+	updatedBlockchain := *blockchain
+
+	updatedBlockchain.Altruist = updateInput.Altruist
+	updatedBlockchain.ChainID = updateInput.ChainID
+	updatedBlockchain.EnforceResult = updateInput.EnforceResult
+	updatedBlockchain.BlockchainAliases = updateInput.BlockchainAliases
+	updatedBlockchain.LogLimitBlocks = updateInput.LogLimitBlocks
+	updatedBlockchain.RequestTimeout = updateInput.RequestTimeout
+	updatedBlockchain.SyncCheckOptions = repository.SyncCheckOptions{}
+	if updateInput.SyncCheckOptions != nil {
+		updatedBlockchain.SyncCheckOptions = *updateInput.SyncCheckOptions
+	}
+	updatedBlockchain.SyncCheckOptions.BlockchainID = blockchain.ID
+
+	rt.respond(w, r, http.StatusOK, &updatedBlockchain)
+}
+
+func (rt *Router) GetBlockchains(w http.ResponseWriter, r *http.Request) {
+	version := rt.Cache.GetCollectionVersion(cache.CollectionBlockchains)
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	respondList(rt, w, r, rt.Cache.GetBlockchains())
+}
+
+func (rt *Router) GetLoadBalancer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	version := rt.Cache.GetEntityVersion(cache.CollectionLoadBalancers, vars["id"])
+
+	lb := rt.Cache.GetLoadBalancer(vars["id"])
+
+	if lb == nil {
+		rt.logError(fmt.Errorf("GetLoadBalancer failed: %w", errBalancerNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errBalancerNotFound.Error())
+		return
+	}
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	rt.respond(w, r, http.StatusOK, rt.withRemoval(lb))
+}
+
+func (rt *Router) GetLoadBalancersByIDs(w http.ResponseWriter, r *http.Request) {
+	var input types.IDs
+
+	decoder := json.NewDecoder(r.Body)
+
+	err := decoder.Decode(&input)
+	if err != nil {
+		rt.logError(fmt.Errorf("GetLoadBalancersByIDs decode failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	if len(input.IDs) == 0 {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, errNoIDs.Error())
+		return
+	}
+
+	lbs, missing := rt.Cache.GetLoadBalancersByIDs(input.IDs)
+
+	rt.respond(w, r, http.StatusOK, types.BatchLoadBalancers{
+		LoadBalancers: lbs,
+		Missing:       missing,
+	})
+}
+
+func (rt *Router) CreateLoadBalancer(w http.ResponseWriter, r *http.Request) {
+	var lb repository.LoadBalancer
+
+	decoder := json.NewDecoder(r.Body)
+
+	err := decoder.Decode(&lb)
+	if err != nil {
+		rt.logError(fmt.Errorf("CreateLoadBalancer Decode failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	unlockQuotas, ok := rt.enforceQuotas(w, r, lb.UserID, quotaChange{loadBalancers: 1})
+	if !ok {
+		return
+	}
+	defer unlockQuotas()
+
+	fullLB, err := rt.Writer.WriteLoadBalancer(&lb)
+	if err != nil {
+		rt.logError(fmt.Errorf("WriteLoadBalancer in CreateLoadBalancer failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	for _, appID := range fullLB.ApplicationIDs {
+		fullLB.Applications = append(fullLB.Applications, rt.Cache.GetApplication(appID))
+	}
+
+	fullLB.ApplicationIDs = nil // set to nil to avoid having two proofs of truth
+
+	rt.respond(w, r, http.StatusOK, fullLB)
+}
+
+// CreateEndpoint creates a load balancer along with its applications in a single transaction, the
+// applications are taken from the cache when their notification already arrived
+func (rt *Router) CreateEndpoint(w http.ResponseWriter, r *http.Request) {
+	var endpoint types.Endpoint
+
+	decoder := json.NewDecoder(r.Body)
+
+	err := decoder.Decode(&endpoint)
+	if err != nil {
+		rt.logError(fmt.Errorf("CreateEndpoint Decode failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	if len(endpoint.Applications) == 0 {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, errNoApplications.Error())
+		return
+	}
+
+	for _, app := range endpoint.Applications {
+		err = types.NormalizeWhitelists(&app.GatewaySettings)
+		if err != nil {
+			jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	payPlans := make([]repository.PayPlanType, 0, len(endpoint.Applications))
+	for _, app := range endpoint.Applications {
+		payPlans = append(payPlans, app.Limit.PayPlan.Type)
+	}
+
+	unlockQuotas, ok := rt.enforceQuotas(w, r, endpoint.LoadBalancer.UserID, quotaChange{
+		applications:  payPlans,
+		loadBalancers: 1,
+	})
+	if !ok {
+		return
+	}
+	defer unlockQuotas()
+
+	written, err := rt.Writer.WriteEndpoint(&endpoint)
+	if err != nil {
+		rt.logError(fmt.Errorf("WriteEndpoint in CreateEndpoint failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writtenApps := make(map[string]*repository.Application, len(written.Applications))
+	for _, app := range written.Applications {
+		writtenApps[app.ID] = app
+	}
+
+	fullLB := written.LoadBalancer
+
+	for _, appID := range fullLB.ApplicationIDs {
+		app := rt.Cache.GetApplication(appID)
+
+		if app == nil && writtenApps[appID] != nil {
+			app = writtenApps[appID]
+
+			rt.setPayPlanLimit(&app.Limit)
+		}
+
+		fullLB.Applications = append(fullLB.Applications, app)
+	}
+
+	fullLB.ApplicationIDs = nil // set to nil to avoid having two proofs of truth
+
+	rt.respond(w, r, http.StatusOK, &fullLB)
+}
+
+func (rt *Router) UpdateLoadBalancer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	lb := rt.Cache.GetLoadBalancer(vars["id"])
+	if lb == nil {
+		rt.logError(fmt.Errorf("GetLoadBalancer in UpdateLoadBalancer failed: %w", errBalancerNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errBalancerNotFound.Error())
+		return
+	}
+
+	var updateInput repository.UpdateLoadBalancer
+
+	decoder := json.NewDecoder(r.Body)
+
+	err := decoder.Decode(&updateInput)
 	if err != nil {
-		rt.logError(fmt.Errorf("CreateBlockchain decode failed: %w", err))
 		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	defer r.Body.Close()
 
-	fullBlockchain, err := rt.Writer.WriteBlockchain(&blockchain)
+	if !updateInput.Remove && rt.isRemoved(lb) {
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, errLoadBalancerRemoved.Error())
+		return
+	}
+
+	ifUpdatedAt, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	if updateInput.Remove {
+		updatedAt, err := rt.Writer.RemoveLoadBalancer(vars["id"], ifUpdatedAt)
+		if preconditionFailed(w, err) {
+			return
+		}
+		if err != nil {
+			rt.logError(fmt.Errorf("RemoveLoadBalancer in UpdateLoadBalancer failed: %w", err))
+			jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// the cache of every instance is updated by the database notification
+		removedLB := *lb
+		removedLB.UserID = ""
+		removedLB.UpdatedAt = updatedAt
+
+		removedAt := time.Now()
+		if removal := rt.Cache.GetLoadBalancerRemoval(lb.ID); removal != nil {
+			removedAt = removal.RemovedAt
+		}
+
+		rt.respond(w, r, http.StatusOK, &types.LoadBalancer{LoadBalancer: &removedLB, RemovedAt: &removedAt})
+		return
+	}
+
+	updatedAt, err := rt.Writer.UpdateLoadBalancer(vars["id"], &updateInput, ifUpdatedAt)
+	if preconditionFailed(w, err) {
+		return
+	}
 	if err != nil {
-		rt.logError(fmt.Errorf("WriteBlockchain in CreateBlockchain failed: %w", err))
+		rt.logError(fmt.Errorf("UpdateLoadBalancer failed: %w", err))
 		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	jsonresponse.RespondWithJSON(w, http.StatusOK, fullBlockchain)
-}
+	if updateInput.Name != "" {
+		lb.Name = updateInput.Name
+	}
+	if updateInput.StickyOptions != nil {
+		lb.StickyOptions = *updateInput.StickyOptions
+	}
+	lb.UpdatedAt = updatedAt
 
-func (rt *Router) GetBlockchains(w http.ResponseWriter, r *http.Request) {
-	jsonresponse.RespondWithJSON(w, http.StatusOK, rt.Cache.GetBlockchains())
+	rt.Cache.BumpVersion(cache.CollectionLoadBalancers, lb.ID)
+
+	rt.respond(w, r, http.StatusOK, lb)
 }
 
-func (rt *Router) GetLoadBalancer(w http.ResponseWriter, r *http.Request) {
+// PatchLoadBalancer applies a JSON merge patch to the load balancer, unlike UpdateLoadBalancer null
+// members clear the fields, the cache of every instance is updated by the database notification
+func (rt *Router) PatchLoadBalancer(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 
 	lb := rt.Cache.GetLoadBalancer(vars["id"])
-
 	if lb == nil {
-		rt.logError(fmt.Errorf("GetLoadBalancer failed: %w", errBalancerNotFound))
+		rt.logError(fmt.Errorf("GetLoadBalancer in PatchLoadBalancer failed: %w", errBalancerNotFound))
 		jsonresponse.RespondWithError(w, http.StatusNotFound, errBalancerNotFound.Error())
 		return
 	}
 
-	jsonresponse.RespondWithJSON(w, http.StatusOK, lb)
-}
+	if rt.isRemoved(lb) {
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, errLoadBalancerRemoved.Error())
+		return
+	}
 
-func (rt *Router) CreateLoadBalancer(w http.ResponseWriter, r *http.Request) {
-	var lb repository.LoadBalancer
+	var patchedLB repository.LoadBalancer
 
-	decoder := json.NewDecoder(r.Body)
+	if !rt.decodeMergePatch(w, r, lb, patchableLoadBalancerFields, &patchedLB) {
+		return
+	}
 
-	err := decoder.Decode(&lb)
-	if err != nil {
-		rt.logError(fmt.Errorf("CreateLoadBalancer Decode failed: %w", err))
-		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+	ifUpdatedAt, ok := ifMatch(w, r)
+	if !ok {
 		return
 	}
 
-	defer r.Body.Close()
+	var err error
 
-	fullLB, err := rt.Writer.WriteLoadBalancer(&lb)
+	patchedLB.UpdatedAt, err = rt.Writer.PatchLoadBalancer(&patchedLB, ifUpdatedAt)
+	if preconditionFailed(w, err) {
+		return
+	}
 	if err != nil {
-		rt.logError(fmt.Errorf("WriteLoadBalancer in CreateLoadBalancer failed: %w", err))
+		rt.logError(fmt.Errorf("PatchLoadBalancer failed: %w", err))
 		jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	for _, appID := range fullLB.ApplicationIDs {
-		fullLB.Applications = append(fullLB.Applications, rt.Cache.GetApplication(appID))
+	rt.respond(w, r, http.StatusOK, &patchedLB)
+}
+
+func (rt *Router) GetLoadBalancers(w http.ResponseWriter, r *http.Request) {
+	version := rt.Cache.GetCollectionVersion(cache.CollectionLoadBalancers, cache.CollectionApplications)
+
+	if rt.notModified(w, r, version) {
+		return
 	}
 
-	fullLB.ApplicationIDs = nil // set to nil to avoid having two proofs of truth
+	includeRemoved, err := parseIncludeRemoved(r)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lbs := rt.Cache.GetLoadBalancers()
+	if !includeRemoved {
+		lbs = rt.activeLoadBalancers(lbs)
+	}
 
-	jsonresponse.RespondWithJSON(w, http.StatusOK, fullLB)
+	lbsWithRemovals := make([]*types.LoadBalancer, 0, len(lbs))
+	for _, lb := range lbs {
+		lbsWithRemovals = append(lbsWithRemovals, rt.withRemoval(lb))
+	}
+
+	respondList(rt, w, r, lbsWithRemovals)
 }
 
-func (rt *Router) UpdateLoadBalancer(w http.ResponseWriter, r *http.Request) {
+// RestoreLoadBalancer gives a removed load balancer back to its owner,
+// the cache of every instance is updated by the database notification
+func (rt *Router) RestoreLoadBalancer(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 
 	lb := rt.Cache.GetLoadBalancer(vars["id"])
 	if lb == nil {
-		rt.logError(fmt.Errorf("GetLoadBalancer in UpdateLoadBalancer failed: %w", errBalancerNotFound))
+		rt.logError(fmt.Errorf("GetLoadBalancer in RestoreLoadBalancer failed: %w", errBalancerNotFound))
 		jsonresponse.RespondWithError(w, http.StatusNotFound, errBalancerNotFound.Error())
 		return
 	}
 
-	var updateInput repository.UpdateLoadBalancer
+	lbRemoval := rt.Cache.GetLoadBalancerRemoval(lb.ID)
+	if lbRemoval == nil {
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, errLoadBalancerNotRemoved.Error())
+		return
+	}
 
-	decoder := json.NewDecoder(r.Body)
+	unlockQuotas, ok := rt.enforceQuotas(w, r, lbRemoval.UserID, quotaChange{loadBalancers: 1})
+	if !ok {
+		return
+	}
+	defer unlockQuotas()
 
-	err := decoder.Decode(&updateInput)
+	removal, err := rt.Writer.RestoreLoadBalancer(lb.ID)
 	if err != nil {
-		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		rt.logError(fmt.Errorf("RestoreLoadBalancer failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
 		return
 	}
 
-	defer r.Body.Close()
+	restoredLB := *lb
+	restoredLB.UserID = removal.UserID
 
-	if updateInput.Remove {
-		err = rt.Writer.RemoveLoadBalancer(vars["id"])
-		if err != nil {
-			rt.logError(fmt.Errorf("RemoveLoadBalancer in UpdateLoadBalancer failed: %w", err))
-			jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
+	rt.respond(w, r, http.StatusOK, &restoredLB)
+}
 
-		lb.UserID = ""
-	} else {
-		err = rt.Writer.UpdateLoadBalancer(vars["id"], &updateInput)
-		if err != nil {
-			rt.logError(fmt.Errorf("UpdateLoadBalancer failed: %w", err))
-			jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
+// isRemoved reports whether the load balancer was removed and not restored yet
+func (rt *Router) isRemoved(lb *repository.LoadBalancer) bool {
+	return rt.Cache.GetLoadBalancerRemoval(lb.ID) != nil
+}
 
-		if updateInput.Name != "" {
-			lb.Name = updateInput.Name
-		}
-		if updateInput.StickyOptions != nil {
-			lb.StickyOptions = *updateInput.StickyOptions
+// withRemoval returns the load balancer along with the time it was removed, if it was
+func (rt *Router) withRemoval(lb *repository.LoadBalancer) *types.LoadBalancer {
+	lbWithRemoval := &types.LoadBalancer{LoadBalancer: lb}
+
+	if removal := rt.Cache.GetLoadBalancerRemoval(lb.ID); removal != nil {
+		removedAt := removal.RemovedAt
+		lbWithRemoval.RemovedAt = &removedAt
+	}
+
+	return lbWithRemoval
+}
+
+// activeLoadBalancers returns the load balancers that were not removed
+func (rt *Router) activeLoadBalancers(lbs []*repository.LoadBalancer) []*repository.LoadBalancer {
+	active := make([]*repository.LoadBalancer, 0, len(lbs))
+
+	for _, lb := range lbs {
+		if !rt.isRemoved(lb) {
+			active = append(active, lb)
 		}
 	}
 
-	jsonresponse.RespondWithJSON(w, http.StatusOK, lb)
+	return active
 }
 
-func (rt *Router) GetLoadBalancers(w http.ResponseWriter, r *http.Request) {
-	jsonresponse.RespondWithJSON(w, http.StatusOK, rt.Cache.GetLoadBalancers())
+func parseIncludeRemoved(r *http.Request) (bool, error) {
+	rawIncludeRemoved := r.URL.Query().Get("include_removed")
+	if rawIncludeRemoved == "" {
+		return false, nil
+	}
+
+	includeRemoved, err := strconv.ParseBool(rawIncludeRemoved)
+	if err != nil {
+		return false, errInvalidIncludeRemoved
+	}
+
+	return includeRemoved, nil
 }
 
 func (rt *Router) GetPayPlan(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 
+	version := rt.Cache.GetEntityVersion(cache.CollectionPayPlans, strings.ToUpper(vars["type"]))
+
 	plan := rt.Cache.GetPayPlan(repository.PayPlanType(strings.ToUpper(vars["type"])))
 
 	if plan == nil {
@@ -514,11 +1920,24 @@ func (rt *Router) GetPayPlan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if rt.notModified(w, r, version) {
+		return
+	}
+
 	jsonresponse.RespondWithJSON(w, http.StatusOK, plan)
 }
 
 func (rt *Router) GetPayPlans(w http.ResponseWriter, r *http.Request) {
-	jsonresponse.RespondWithJSON(w, http.StatusOK, rt.Cache.GetPayPlans())
+	version := rt.Cache.GetCollectionVersion(cache.CollectionPayPlans)
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	err := respondWithJSONList(w, http.StatusOK, rt.Cache.GetPayPlans(), encoder{})
+	if err != nil {
+		rt.logError(fmt.Errorf("GetPayPlans write failed: %w", err))
+	}
 }
 
 func (rt *Router) CreateRedirect(w http.ResponseWriter, r *http.Request) {
@@ -544,3 +1963,208 @@ func (rt *Router) CreateRedirect(w http.ResponseWriter, r *http.Request) {
 
 	jsonresponse.RespondWithJSON(w, http.StatusOK, fullRedirect)
 }
+
+func (rt *Router) GetRedirectByDomain(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	version := rt.Cache.GetCollectionVersion(cache.CollectionRedirects)
+
+	redirect := rt.Cache.GetRedirectByDomain(vars["domain"])
+
+	if redirect == nil {
+		rt.logError(fmt.Errorf("GetRedirectByDomain failed: %w", errRedirectNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errRedirectNotFound.Error())
+		return
+	}
+
+	if rt.notModified(w, r, version) {
+		return
+	}
+
+	jsonresponse.RespondWithJSON(w, http.StatusOK, redirect)
+}
+
+func (rt *Router) GetChanges(w http.ResponseWriter, r *http.Request) {
+	var since uint64
+
+	resync := true
+
+	if token := r.URL.Query().Get("since"); token != "" {
+		generation, sameInstance, err := rt.parseVersionToken(token)
+		if err != nil {
+			jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if sameInstance {
+			since, resync = generation, false
+		}
+	}
+
+	changes := rt.Cache.GetChangesSince(since)
+
+	removed := []types.Tombstone{}
+	for _, removal := range changes.Removed {
+		removed = append(removed, types.Tombstone{
+			Collection: string(removal.Collection),
+			ID:         removal.ID,
+		})
+	}
+
+	rt.respond(w, r, http.StatusOK, types.Changes{
+		Version:       rt.versionToken(changes.Generation),
+		Resync:        resync || changes.Resync,
+		Applications:  changes.Applications,
+		LoadBalancers: changes.LoadBalancers,
+		Blockchains:   changes.Blockchains,
+		Removed:       removed,
+	})
+}
+
+// decodeTransfer decodes the transfer input making sure it has the user to transfer to
+func decodeTransfer(r *http.Request) (types.Transfer, error) {
+	var transfer types.Transfer
+
+	decoder := json.NewDecoder(r.Body)
+
+	err := decoder.Decode(&transfer)
+	if err != nil {
+		return types.Transfer{}, err
+	}
+
+	defer r.Body.Close()
+
+	if transfer.UserID == "" {
+		return types.Transfer{}, errNoUserID
+	}
+
+	return transfer, nil
+}
+
+// TransferApplication gives the ownership of the application to another user,
+// the cache of every instance is updated by the database notification
+func (rt *Router) TransferApplication(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	app := rt.Cache.GetApplication(vars["id"])
+	if app == nil {
+		rt.logError(fmt.Errorf("GetApplication in TransferApplication failed: %w", errApplicationNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errApplicationNotFound.Error())
+		return
+	}
+
+	transfer, err := decodeTransfer(r)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var change quotaChange
+	if transfer.UserID != app.UserID && app.Status != repository.AwaitingGracePeriod {
+		change.applications = []repository.PayPlanType{app.Limit.PayPlan.Type}
+	}
+
+	unlockQuotas, ok := rt.enforceQuotas(w, r, transfer.UserID, change)
+	if !ok {
+		return
+	}
+	defer unlockQuotas()
+
+	err = rt.Writer.TransferApplication(app.ID, transfer.UserID)
+	if err != nil {
+		rt.logError(fmt.Errorf("TransferApplication failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	transferredApp := *app
+	transferredApp.UserID = transfer.UserID
+
+	rt.respond(w, r, http.StatusOK, &transferredApp)
+}
+
+// TransferLoadBalancer gives the ownership of the load balancer to another user, its applications
+// keep their owner, the cache of every instance is updated by the database notification
+func (rt *Router) TransferLoadBalancer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	lb := rt.Cache.GetLoadBalancer(vars["id"])
+	if lb == nil {
+		rt.logError(fmt.Errorf("GetLoadBalancer in TransferLoadBalancer failed: %w", errBalancerNotFound))
+		jsonresponse.RespondWithError(w, http.StatusNotFound, errBalancerNotFound.Error())
+		return
+	}
+
+	if rt.isRemoved(lb) {
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, errLoadBalancerRemoved.Error())
+		return
+	}
+
+	transfer, err := decodeTransfer(r)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var change quotaChange
+	if transfer.UserID != lb.UserID {
+		change.loadBalancers = 1
+	}
+
+	unlockQuotas, ok := rt.enforceQuotas(w, r, transfer.UserID, change)
+	if !ok {
+		return
+	}
+	defer unlockQuotas()
+
+	err = rt.Writer.TransferLoadBalancer(lb.ID, transfer.UserID)
+	if err != nil {
+		rt.logError(fmt.Errorf("TransferLoadBalancer failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	transferredLB := *lb
+	transferredLB.UserID = transfer.UserID
+
+	rt.respond(w, r, http.StatusOK, &transferredLB)
+}
+
+// TransferUserEntities gives the ownership of every application and load balancer of the user to another
+// user atomically, the cache of every instance is updated by the database notifications
+func (rt *Router) TransferUserEntities(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	transfer, err := decodeTransfer(r)
+	if err != nil {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var change quotaChange
+	if transfer.UserID != vars["id"] && rt.Quotas.limited(transfer.UserID) {
+		counts, err := rt.Writer.ReadUserEntityCounts(vars["id"])
+		if err != nil {
+			rt.logError(fmt.Errorf("ReadUserEntityCounts in TransferUserEntities failed: %w", err))
+			jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		change = countsQuotaChange(counts)
+	}
+
+	unlockQuotas, ok := rt.enforceQuotas(w, r, transfer.UserID, change)
+	if !ok {
+		return
+	}
+	defer unlockQuotas()
+
+	transferred, err := rt.Writer.TransferUserEntities(vars["id"], transfer.UserID)
+	if err != nil {
+		rt.logError(fmt.Errorf("TransferUserEntities failed: %w", err))
+		jsonresponse.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	jsonresponse.RespondWithJSON(w, http.StatusOK, transferred)
+}
@@ -2,14 +2,25 @@ package router
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/andybalholm/brotli"
+	"github.com/pokt-foundation/pocket-http-db/aat"
 	"github.com/pokt-foundation/pocket-http-db/cache"
+	"github.com/pokt-foundation/pocket-http-db/types"
 	"github.com/pokt-foundation/portal-api-go/repository"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/mock"
@@ -26,16 +37,16 @@ func (w *writerMock) WriteLoadBalancer(loadBalancer *repository.LoadBalancer) (*
 	return args.Get(0).(*repository.LoadBalancer), args.Error(1)
 }
 
-func (w *writerMock) UpdateLoadBalancer(id string, options *repository.UpdateLoadBalancer) error {
-	args := w.Called()
+func (w *writerMock) UpdateLoadBalancer(id string, options *repository.UpdateLoadBalancer, ifUpdatedAt *time.Time) (time.Time, error) {
+	args := w.Called(ifUpdatedAt)
 
-	return args.Error(0)
+	return args.Get(0).(time.Time), args.Error(1)
 }
 
-func (w *writerMock) RemoveLoadBalancer(id string) error {
-	args := w.Called()
+func (w *writerMock) RemoveLoadBalancer(id string, ifUpdatedAt *time.Time) (time.Time, error) {
+	args := w.Called(ifUpdatedAt)
 
-	return args.Error(0)
+	return args.Get(0).(time.Time), args.Error(1)
 }
 
 func (w *writerMock) WriteApplication(app *repository.Application) (*repository.Application, error) {
@@ -44,10 +55,10 @@ func (w *writerMock) WriteApplication(app *repository.Application) (*repository.
 	return args.Get(0).(*repository.Application), args.Error(1)
 }
 
-func (w *writerMock) UpdateApplication(id string, options *repository.UpdateApplication) error {
-	args := w.Called()
+func (w *writerMock) UpdateApplication(id string, options *repository.UpdateApplication, ifUpdatedAt *time.Time) (time.Time, error) {
+	args := w.Called(ifUpdatedAt)
 
-	return args.Error(0)
+	return args.Get(0).(time.Time), args.Error(1)
 }
 
 func (w *writerMock) UpdateFirstDateSurpassed(firstDateSurpassed *repository.UpdateFirstDateSurpassed) error {
@@ -56,10 +67,22 @@ func (w *writerMock) UpdateFirstDateSurpassed(firstDateSurpassed *repository.Upd
 	return args.Error(0)
 }
 
-func (w *writerMock) RemoveApplication(id string) error {
-	args := w.Called()
+func (w *writerMock) ReadUsageState(id string) (*types.UsageState, error) {
+	args := w.Called(id)
 
-	return args.Error(0)
+	return args.Get(0).(*types.UsageState), args.Error(1)
+}
+
+func (w *writerMock) UpdateUsageStates(updates []*types.UsageStateUpdate) ([]*types.UsageState, []error, error) {
+	args := w.Called(updates)
+
+	return args.Get(0).([]*types.UsageState), args.Get(1).([]error), args.Error(2)
+}
+
+func (w *writerMock) RemoveApplication(id string, ifUpdatedAt *time.Time) (time.Time, error) {
+	args := w.Called(ifUpdatedAt)
+
+	return args.Get(0).(time.Time), args.Error(1)
 }
 
 func (w *writerMock) WriteBlockchain(blockchain *repository.Blockchain) (*repository.Blockchain, error) {
@@ -80,7 +103,126 @@ func (w *writerMock) ActivateBlockchain(id string, active bool) error {
 	return args.Error(0)
 }
 
+func (w *writerMock) RestoreApplication(id string) (*types.ApplicationRemoval, error) {
+	args := w.Called()
+
+	return args.Get(0).(*types.ApplicationRemoval), args.Error(1)
+}
+
+func (w *writerMock) RestoreLoadBalancer(id string) (*types.LoadBalancerRemoval, error) {
+	args := w.Called()
+
+	return args.Get(0).(*types.LoadBalancerRemoval), args.Error(1)
+}
+
+func (w *writerMock) TransferApplication(id, userID string) error {
+	args := w.Called()
+
+	return args.Error(0)
+}
+
+func (w *writerMock) TransferLoadBalancer(id, userID string) error {
+	args := w.Called()
+
+	return args.Error(0)
+}
+
+func (w *writerMock) TransferUserEntities(fromUserID, toUserID string) (*types.TransferredEntities, error) {
+	args := w.Called()
+
+	return args.Get(0).(*types.TransferredEntities), args.Error(1)
+}
+
+func (w *writerMock) WriteEndpoint(endpoint *types.Endpoint) (*types.Endpoint, error) {
+	args := w.Called()
+
+	return args.Get(0).(*types.Endpoint), args.Error(1)
+}
+
+func (w *writerMock) ReserveIdempotencyKey(key, caller, requestHash string, ttl, lockTimeout time.Duration) (*types.IdempotentRequest, error) {
+	args := w.Called(key, requestHash)
+
+	return args.Get(0).(*types.IdempotentRequest), args.Error(1)
+}
+
+func (w *writerMock) SaveIdempotentResponse(key, caller string, response *types.IdempotentRequest) error {
+	args := w.Called(key, response)
+
+	return args.Error(0)
+}
+
+func (w *writerMock) ReleaseIdempotencyKey(key, caller string) error {
+	args := w.Called(key)
+
+	return args.Error(0)
+}
+
+func (w *writerMock) LockUserQuota(ctx context.Context, userID string) (func(), error) {
+	args := w.Called(userID)
+
+	return func() {}, args.Error(0)
+}
+
+func (w *writerMock) ReadUserEntityCounts(userID string) (*types.UserEntityCounts, error) {
+	args := w.Called(userID)
+
+	return args.Get(0).(*types.UserEntityCounts), args.Error(1)
+}
+
+func (w *writerMock) PatchApplication(app *repository.Application, ifUpdatedAt *time.Time) (time.Time, error) {
+	args := w.Called(app, ifUpdatedAt)
+
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (w *writerMock) PatchApplications(apps []*repository.Application) error {
+	args := w.Called(apps)
+
+	return args.Error(0)
+}
+
+func (w *writerMock) PatchLoadBalancer(lb *repository.LoadBalancer, ifUpdatedAt *time.Time) (time.Time, error) {
+	args := w.Called(lb, ifUpdatedAt)
+
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (w *writerMock) UpdateWhitelist(id string, change *types.WhitelistChange) (*repository.GatewaySettings, time.Time, error) {
+	args := w.Called(id, *change)
+
+	return args.Get(0).(*repository.GatewaySettings), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (w *writerMock) UpdateGatewayAAT(id string, aat *repository.GatewayAAT) error {
+	args := w.Called()
+
+	return args.Error(0)
+}
+
+func (w *writerMock) UpdateSecretKey(id, secretKey string) error {
+	args := w.Called()
+
+	return args.Error(0)
+}
+
+func (w *writerMock) UpdateBlockchain(id string, options *types.UpdateBlockchain) error {
+	args := w.Called()
+
+	return args.Error(0)
+}
+
 func newTestRouter() (*Router, error) {
+	return newTestRouterWithBlockchains([]*repository.Blockchain{
+		{
+			ID: "0021",
+		},
+		{
+			ID: "0022",
+		},
+	})
+}
+
+func newTestRouterWithBlockchains(blockchains []*repository.Blockchain) (*Router, error) {
 	readerMock := &cache.ReaderMock{}
 
 	readerMock.On("ReadPayPlans").Return([]*repository.PayPlan{
@@ -128,17 +270,13 @@ func newTestRouter() (*Router, error) {
 		{
 			ID:     "5f62b7d8be3591c4dea8566f",
 			UserID: "60ecb2bf67774900350d9c44",
+			GatewayAAT: repository.GatewayAAT{
+				ApplicationPublicKey: "a2f1b3c4d5e6f708",
+			},
 		},
 	}, nil)
 
-	readerMock.On("ReadBlockchains").Return([]*repository.Blockchain{
-		{
-			ID: "0021",
-		},
-		{
-			ID: "0022",
-		},
-	}, nil)
+	readerMock.On("ReadBlockchains").Return(blockchains, nil)
 
 	readerMock.On("ReadLoadBalancers").Return([]*repository.LoadBalancer{
 		{
@@ -158,7 +296,15 @@ func newTestRouter() (*Router, error) {
 		},
 	}, nil)
 
-	return NewRouter(readerMock, nil, map[string]bool{"": true}, logrus.New())
+	readerMock.On("ReadLoadBalancerRemovals").Return([]*types.LoadBalancerRemoval{
+		{
+			LoadBalancerID: "60ecb2bf67774900350d9c43",
+			UserID:         "60ecb2bf67774900350d9c43",
+			RemovedAt:      time.Date(2022, time.July, 21, 0, 0, 0, 0, time.UTC),
+		},
+	}, nil)
+
+	return NewRouter(readerMock, nil, map[string]bool{"": true}, nil, Quotas{}, logrus.New())
 }
 
 func TestRouter_HealthCheck(t *testing.T) {
@@ -211,6 +357,9 @@ func TestRouter_GetApplications(t *testing.T) {
 		{
 			ID:     "5f62b7d8be3591c4dea8566f",
 			UserID: "60ecb2bf67774900350d9c44",
+			GatewayAAT: repository.GatewayAAT{
+				ApplicationPublicKey: "a2f1b3c4d5e6f708",
+			},
 		},
 	})
 	c.NoError(err)
@@ -226,6 +375,134 @@ func TestRouter_GetApplications(t *testing.T) {
 	c.Equal(http.StatusUnauthorized, rr.Code)
 }
 
+func TestRouter_ConditionalGet(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	req, err := http.NewRequest(http.MethodGet, "/application/5f62b7d8be3591c4dea8566d", nil)
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	etag := rr.Header().Get("ETag")
+	c.NotEmpty(etag)
+	c.NotEmpty(rr.Header().Get("Last-Modified"))
+
+	req.Header.Set("If-None-Match", etag)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotModified, rr.Code)
+	c.Empty(rr.Body.Bytes())
+	c.Equal(etag, rr.Header().Get("ETag"))
+
+	req.Header.Set("If-None-Match", `"other", W/`+etag)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotModified, rr.Code)
+
+	req.Header.Set("If-None-Match", `"other"`)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	req, err = http.NewRequest(http.MethodGet, "/application", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	collectionETag := rr.Header().Get("ETag")
+	c.NotEmpty(collectionETag)
+
+	req.Header.Set("If-None-Match", collectionETag)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotModified, rr.Code)
+}
+
+func TestRouter_ETagVariants(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	router.APIKeys = map[string]bool{"reader": true, "admin": true}
+	router.Capabilities = map[string]map[Capability]bool{"admin": {CapabilitySecretsRead: true}}
+
+	getETag := func(path, apiKey, acceptEncoding string) string {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		c.NoError(err)
+
+		req.Header.Set("Authorization", apiKey)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+
+		rr := httptest.NewRecorder()
+
+		router.Router.ServeHTTP(rr, req)
+
+		c.Equal(http.StatusOK, rr.Code)
+		c.Contains(rr.Header().Values("Vary"), "Authorization")
+
+		return rr.Header().Get("ETag")
+	}
+
+	path := "/application/5f62b7d8be3591c4dea8566d"
+	etag := getETag(path, "reader", "")
+
+	etags := map[string]bool{
+		etag:                                       true,
+		getETag(path, "admin", ""):                 true,
+		getETag(path, "reader", "gzip"):            true,
+		getETag(path, "reader", "br"):              true,
+		getETag(path+"?fields=id", "reader", ""):   true,
+		getETag(path+"?fields=name", "reader", ""): true,
+	}
+	c.Len(etags, 6)
+
+	c.Equal(etag, getETag(path, "reader", ""))
+
+	writerMock := &writerMock{}
+
+	writerMock.On("UpdateApplication", mock.Anything).Return(time.Time{}, nil).Once()
+
+	router.Writer = writerMock
+
+	req, err := http.NewRequest(http.MethodPut, path, bytes.NewBufferString(`{"name":"renamed"}`))
+	c.NoError(err)
+
+	req.Header.Set("Authorization", "reader")
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	// Updates applied to the cached application move it to a new version
+	c.NotEqual(etag, getETag(path, "reader", ""))
+}
+
 func TestRouter_GetApplicationsLimits(t *testing.T) {
 	c := require.New(t)
 
@@ -261,6 +538,7 @@ func TestRouter_GetApplicationsLimits(t *testing.T) {
 		{
 			AppID:                "5f62b7d8be3591c4dea8566f",
 			AppUserID:            "60ecb2bf67774900350d9c44",
+			PublicKey:            "a2f1b3c4d5e6f708",
 			DailyLimit:           0,
 			NotificationSettings: &repository.NotificationSettings{},
 		},
@@ -310,23 +588,15 @@ func TestRouter_GetApplication(t *testing.T) {
 	c.Equal(http.StatusNotFound, rr.Code)
 }
 
-func TestRouter_CreateApplication(t *testing.T) {
+func TestRouter_GetApplicationsByIDs(t *testing.T) {
 	c := require.New(t)
 
-	rawAppToSend := &repository.Application{
-		UserID: "60ddc61b6e29c3003378361D",
-		Limit: repository.AppLimit{
-			PayPlan: repository.PayPlan{
-				Type:  repository.FreetierV0,
-				Limit: 250000,
-			},
-		},
-	}
-
-	appToSend, err := json.Marshal(rawAppToSend)
+	rawReq, err := json.Marshal(types.IDs{
+		IDs: []string{"5f62b7d8be3591c4dea8566a", "5f62b7d8be3591c4dea8566b"},
+	})
 	c.NoError(err)
 
-	req, err := http.NewRequest(http.MethodPost, "/application", bytes.NewBuffer(appToSend))
+	req, err := http.NewRequest(http.MethodPost, "/application/batch", bytes.NewBuffer(rawReq))
 	c.NoError(err)
 
 	rr := httptest.NewRecorder()
@@ -334,42 +604,24 @@ func TestRouter_CreateApplication(t *testing.T) {
 	router, err := newTestRouter()
 	c.NoError(err)
 
-	appToReturn := &repository.Application{
-		ID:     "60ddc61b6e29c3003378361E",
-		UserID: "60ddc61b6e29c3003378361D",
-		Limit: repository.AppLimit{
-			PayPlan: repository.PayPlan{
-				Type:  repository.FreetierV0,
-				Limit: 250000,
-			},
-		},
-	}
-
-	writerMock := &writerMock{}
-
-	writerMock.On("WriteApplication", mock.Anything).Return(appToReturn, nil).Once()
-
-	router.Writer = writerMock
-
 	router.Router.ServeHTTP(rr, req)
 
 	c.Equal(http.StatusOK, rr.Code)
 
-	marshaledReturnApp, err := json.Marshal(&repository.Application{
-		ID:     "60ddc61b6e29c3003378361E",
-		UserID: "60ddc61b6e29c3003378361D",
-		Limit: repository.AppLimit{
-			PayPlan: repository.PayPlan{
-				Type:  repository.FreetierV0,
-				Limit: 250000,
+	expectedBody, err := json.Marshal(types.BatchApplications{
+		Applications: []*repository.Application{
+			{
+				ID:     "5f62b7d8be3591c4dea8566a",
+				UserID: "60ecb2bf67774900350d9c43",
 			},
 		},
+		Missing: []string{"5f62b7d8be3591c4dea8566b"},
 	})
 	c.NoError(err)
 
-	c.Equal(marshaledReturnApp, rr.Body.Bytes())
+	c.Equal(expectedBody, rr.Body.Bytes())
 
-	req, err = http.NewRequest(http.MethodPost, "/application", bytes.NewBuffer([]byte("wrong")))
+	req, err = http.NewRequest(http.MethodPost, "/application/batch", bytes.NewBufferString(`{"ids":[]}`))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
@@ -378,70 +630,89 @@ func TestRouter_CreateApplication(t *testing.T) {
 
 	c.Equal(http.StatusBadRequest, rr.Code)
 
-	req, err = http.NewRequest(http.MethodPost, "/application", bytes.NewBuffer(appToSend))
+	req, err = http.NewRequest(http.MethodPost, "/application/batch", bytes.NewBufferString("wrong"))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
 
-	writerMock.On("WriteApplication", mock.Anything).Return(appToReturn, errors.New("dummy error")).Once()
-
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusInternalServerError, rr.Code)
+	c.Equal(http.StatusBadRequest, rr.Code)
 }
 
-func TestRouter_UpdateApplication(t *testing.T) {
+func TestRouter_GetApplicationByPublicKey(t *testing.T) {
 	c := require.New(t)
 
-	rawUpdateInput := &repository.UpdateApplication{
-		Name:   "pablo",
-		Status: repository.Orphaned,
-		Limit: &repository.AppLimit{
-			PayPlan: repository.PayPlan{
-				Type:  repository.PayAsYouGoV0,
-				Limit: 0,
-			},
-		},
-		FirstDateSurpassed: time.Now(),
-		GatewaySettings: &repository.GatewaySettings{
-			SecretKey: "1234",
-		},
-		NotificationSettings: &repository.NotificationSettings{
-			Half: true,
-		},
-	}
+	req, err := http.NewRequest(http.MethodGet, "/application/public_key/a2f1b3c4d5e6f708", nil)
+	c.NoError(err)
 
-	updateInputToSend, err := json.Marshal(rawUpdateInput)
-	c.NoError(err)
+	rr := httptest.NewRecorder()
 
-	req, err := http.NewRequest(http.MethodPut, "/application/5f62b7d8be3591c4dea8566d", bytes.NewBuffer(updateInputToSend))
+	router, err := newTestRouter()
 	c.NoError(err)
 
-	rr := httptest.NewRecorder()
+	router.Router.ServeHTTP(rr, req)
 
-	router, err := newTestRouter()
+	c.Equal(http.StatusOK, rr.Code)
+
+	expectedBody, err := json.Marshal(&repository.Application{
+		ID:     "5f62b7d8be3591c4dea8566f",
+		UserID: "60ecb2bf67774900350d9c44",
+		GatewayAAT: repository.GatewayAAT{
+			ApplicationPublicKey: "a2f1b3c4d5e6f708",
+		},
+	})
 	c.NoError(err)
 
-	writerMock := &writerMock{}
+	c.Equal(expectedBody, rr.Body.Bytes())
 
-	writerMock.On("UpdateApplication", mock.Anything).Return(nil).Once()
+	req, err = http.NewRequest(http.MethodGet, "/application/public_key/ffffffffffffffff", nil)
+	c.NoError(err)
 
-	router.Writer = writerMock
+	rr = httptest.NewRecorder()
 
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusOK, rr.Code)
+	c.Equal(http.StatusNotFound, rr.Code)
+}
 
-	req, err = http.NewRequest(http.MethodPut, "/application/5f62b7d8be3591c4dea85664", bytes.NewBuffer(updateInputToSend))
+func TestRouter_GetApplicationsByPublicKeys(t *testing.T) {
+	c := require.New(t)
+
+	rawReq, err := json.Marshal(types.PublicKeys{
+		PublicKeys: []string{"a2f1b3c4d5e6f708", "ffffffffffffffff"},
+	})
 	c.NoError(err)
 
-	rr = httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPost, "/application/public_key", bytes.NewBuffer(rawReq))
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router, err := newTestRouter()
+	c.NoError(err)
 
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusNotFound, rr.Code)
+	c.Equal(http.StatusOK, rr.Code)
 
-	req, err = http.NewRequest(http.MethodPut, "/application/5f62b7d8be3591c4dea8566d", bytes.NewBuffer([]byte("wrong")))
+	expectedBody, err := json.Marshal(types.BatchApplications{
+		Applications: []*repository.Application{
+			{
+				ID:     "5f62b7d8be3591c4dea8566f",
+				UserID: "60ecb2bf67774900350d9c44",
+				GatewayAAT: repository.GatewayAAT{
+					ApplicationPublicKey: "a2f1b3c4d5e6f708",
+				},
+			},
+		},
+		Missing: []string{"ffffffffffffffff"},
+	})
+	c.NoError(err)
+
+	c.Equal(expectedBody, rr.Body.Bytes())
+
+	req, err = http.NewRequest(http.MethodPost, "/application/public_key", bytes.NewBufferString(`{"publicKeys":[]}`))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
@@ -450,30 +721,33 @@ func TestRouter_UpdateApplication(t *testing.T) {
 
 	c.Equal(http.StatusBadRequest, rr.Code)
 
-	req, err = http.NewRequest(http.MethodPut, "/application/5f62b7d8be3591c4dea8566d", bytes.NewBuffer(updateInputToSend))
+	req, err = http.NewRequest(http.MethodPost, "/application/public_key", bytes.NewBufferString("wrong"))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
 
-	writerMock.On("UpdateApplication", mock.Anything).Return(errors.New("dummy error")).Once()
-
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+	c.Equal(http.StatusBadRequest, rr.Code)
 }
 
-func TestRouter_UpdateFirstDateSurpassed(t *testing.T) {
+func TestRouter_CreateApplication(t *testing.T) {
 	c := require.New(t)
 
-	rawUpdateInput := &repository.UpdateFirstDateSurpassed{
-		ApplicationIDs:     []string{"5f62b7d8be3591c4dea8566d"},
-		FirstDateSurpassed: time.Now(),
+	rawAppToSend := &repository.Application{
+		UserID: "60ddc61b6e29c3003378361D",
+		Limit: repository.AppLimit{
+			PayPlan: repository.PayPlan{
+				Type:  repository.FreetierV0,
+				Limit: 250000,
+			},
+		},
 	}
 
-	updateInputToSend, err := json.Marshal(rawUpdateInput)
+	appToSend, err := json.Marshal(rawAppToSend)
 	c.NoError(err)
 
-	req, err := http.NewRequest(http.MethodPost, "/application/first_date_surpassed", bytes.NewBuffer(updateInputToSend))
+	req, err := http.NewRequest(http.MethodPost, "/application", bytes.NewBuffer(appToSend))
 	c.NoError(err)
 
 	rr := httptest.NewRecorder()
@@ -481,9 +755,20 @@ func TestRouter_UpdateFirstDateSurpassed(t *testing.T) {
 	router, err := newTestRouter()
 	c.NoError(err)
 
+	appToReturn := &repository.Application{
+		ID:     "60ddc61b6e29c3003378361E",
+		UserID: "60ddc61b6e29c3003378361D",
+		Limit: repository.AppLimit{
+			PayPlan: repository.PayPlan{
+				Type:  repository.FreetierV0,
+				Limit: 250000,
+			},
+		},
+	}
+
 	writerMock := &writerMock{}
 
-	writerMock.On("UpdateFirstDateSurpassed", mock.Anything).Return(nil).Once()
+	writerMock.On("WriteApplication", mock.Anything).Return(appToReturn, nil).Once()
 
 	router.Writer = writerMock
 
@@ -491,18 +776,21 @@ func TestRouter_UpdateFirstDateSurpassed(t *testing.T) {
 
 	c.Equal(http.StatusOK, rr.Code)
 
-	writerMock.On("UpdateFirstDateSurpassed", mock.Anything).Return(errors.New("dummy error")).Once()
-
-	req, err = http.NewRequest(http.MethodPost, "/application/first_date_surpassed", bytes.NewBuffer(updateInputToSend))
+	marshaledReturnApp, err := json.Marshal(&repository.Application{
+		ID:     "60ddc61b6e29c3003378361E",
+		UserID: "60ddc61b6e29c3003378361D",
+		Limit: repository.AppLimit{
+			PayPlan: repository.PayPlan{
+				Type:  repository.FreetierV0,
+				Limit: 250000,
+			},
+		},
+	})
 	c.NoError(err)
 
-	rr = httptest.NewRecorder()
-
-	router.Router.ServeHTTP(rr, req)
-
-	c.Equal(http.StatusInternalServerError, rr.Code)
+	c.Equal(marshaledReturnApp, rr.Body.Bytes())
 
-	req, err = http.NewRequest(http.MethodPost, "/application/first_date_surpassed", bytes.NewBuffer([]byte("wrong")))
+	req, err = http.NewRequest(http.MethodPost, "/application", bytes.NewBuffer([]byte("wrong")))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
@@ -511,43 +799,40 @@ func TestRouter_UpdateFirstDateSurpassed(t *testing.T) {
 
 	c.Equal(http.StatusBadRequest, rr.Code)
 
-	rawUpdateInput.ApplicationIDs = []string{"5f62b7d8be3591c4dea85664"}
-
-	updateInputToSend, err = json.Marshal(rawUpdateInput)
-	c.NoError(err)
-
-	req, err = http.NewRequest(http.MethodPost, "/application/first_date_surpassed", bytes.NewBuffer(updateInputToSend))
+	req, err = http.NewRequest(http.MethodPost, "/application", bytes.NewBuffer(appToSend))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
 
-	router.Router.ServeHTTP(rr, req)
-
-	c.Equal(http.StatusNotFound, rr.Code)
-
-	rawUpdateInput.ApplicationIDs = nil
-
-	updateInputToSend, err = json.Marshal(rawUpdateInput)
-	c.NoError(err)
-
-	req, err = http.NewRequest(http.MethodPost, "/application/first_date_surpassed", bytes.NewBuffer(updateInputToSend))
-	c.NoError(err)
-
-	rr = httptest.NewRecorder()
+	writerMock.On("WriteApplication", mock.Anything).Return(appToReturn, errors.New("dummy error")).Once()
 
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusBadRequest, rr.Code)
+	c.Equal(http.StatusInternalServerError, rr.Code)
 }
 
-func TestRouter_RemoveApplication(t *testing.T) {
+func TestRouter_UpdateApplication(t *testing.T) {
 	c := require.New(t)
 
-	rawRemoveInput := &repository.UpdateApplication{
-		Remove: true,
+	rawUpdateInput := &repository.UpdateApplication{
+		Name:   "pablo",
+		Status: repository.Orphaned,
+		Limit: &repository.AppLimit{
+			PayPlan: repository.PayPlan{
+				Type:  repository.PayAsYouGoV0,
+				Limit: 0,
+			},
+		},
+		FirstDateSurpassed: time.Now(),
+		GatewaySettings: &repository.GatewaySettings{
+			SecretKey: "1234",
+		},
+		NotificationSettings: &repository.NotificationSettings{
+			Half: true,
+		},
 	}
 
-	updateInputToSend, err := json.Marshal(rawRemoveInput)
+	updateInputToSend, err := json.Marshal(rawUpdateInput)
 	c.NoError(err)
 
 	req, err := http.NewRequest(http.MethodPut, "/application/5f62b7d8be3591c4dea8566d", bytes.NewBuffer(updateInputToSend))
@@ -560,7 +845,7 @@ func TestRouter_RemoveApplication(t *testing.T) {
 
 	writerMock := &writerMock{}
 
-	writerMock.On("RemoveApplication", mock.Anything).Return(nil).Once()
+	writerMock.On("UpdateApplication", mock.Anything).Return(time.Time{}, nil).Once()
 
 	router.Writer = writerMock
 
@@ -586,22 +871,40 @@ func TestRouter_RemoveApplication(t *testing.T) {
 
 	c.Equal(http.StatusBadRequest, rr.Code)
 
+	req, err = http.NewRequest(http.MethodPut, "/application/5f62b7d8be3591c4dea8566d",
+		bytes.NewBufferString(`{"gatewaySettings":{"whitelistContracts":[{"blockchainID":"0021","contracts":["0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"]}]}}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
 	req, err = http.NewRequest(http.MethodPut, "/application/5f62b7d8be3591c4dea8566d", bytes.NewBuffer(updateInputToSend))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
 
-	writerMock.On("RemoveApplication", mock.Anything).Return(errors.New("dummy error")).Once()
+	writerMock.On("UpdateApplication", mock.Anything).Return(time.Time{}, errors.New("dummy error")).Once()
 
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusInternalServerError, rr.Code)
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
 }
 
-func TestRouter_GetApplicationsByUserID(t *testing.T) {
+func TestRouter_UpdateFirstDateSurpassed(t *testing.T) {
 	c := require.New(t)
 
-	req, err := http.NewRequest(http.MethodGet, "/user/60ecb2bf67774900350d9c43/application", nil)
+	rawUpdateInput := &repository.UpdateFirstDateSurpassed{
+		ApplicationIDs:     []string{"5f62b7d8be3591c4dea8566d"},
+		FirstDateSurpassed: time.Now(),
+	}
+
+	updateInputToSend, err := json.Marshal(rawUpdateInput)
+	c.NoError(err)
+
+	req, err := http.NewRequest(http.MethodPost, "/application/first_date_surpassed", bytes.NewBuffer(updateInputToSend))
 	c.NoError(err)
 
 	rr := httptest.NewRecorder()
@@ -609,290 +912,376 @@ func TestRouter_GetApplicationsByUserID(t *testing.T) {
 	router, err := newTestRouter()
 	c.NoError(err)
 
+	writerMock := &writerMock{}
+
+	writerMock.On("UpdateFirstDateSurpassed", mock.Anything).Return(nil).Once()
+
+	router.Writer = writerMock
+
 	router.Router.ServeHTTP(rr, req)
 
 	c.Equal(http.StatusOK, rr.Code)
 
-	expectedBody, err := json.Marshal([]*repository.Application{
-		{
-			ID:     "5f62b7d8be3591c4dea8566d",
-			UserID: "60ecb2bf67774900350d9c43",
-			Limit: repository.AppLimit{
-				PayPlan: repository.PayPlan{
-					Type:  repository.FreetierV0,
-					Limit: 250000,
-				},
-			},
-			FirstDateSurpassed: time.Date(2022, time.July, 21, 0, 0, 0, 0, time.UTC),
-		},
-		{
-			ID:     "5f62b7d8be3591c4dea8566a",
-			UserID: "60ecb2bf67774900350d9c43",
-		},
-	})
+	writerMock.On("UpdateFirstDateSurpassed", mock.Anything).Return(errors.New("dummy error")).Once()
+
+	req, err = http.NewRequest(http.MethodPost, "/application/first_date_surpassed", bytes.NewBuffer(updateInputToSend))
 	c.NoError(err)
 
-	c.Equal(expectedBody, rr.Body.Bytes())
+	rr = httptest.NewRecorder()
 
-	req, err = http.NewRequest(http.MethodGet, "/user/61ecb2bf67774900350d9c43/application", nil)
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusInternalServerError, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/application/first_date_surpassed", bytes.NewBuffer([]byte("wrong")))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
 
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusNotFound, rr.Code)
-}
+	c.Equal(http.StatusBadRequest, rr.Code)
 
-func TestRouter_GetLoadbalancerByUserID(t *testing.T) {
-	c := require.New(t)
+	rawUpdateInput.ApplicationIDs = []string{"5f62b7d8be3591c4dea85664"}
 
-	req, err := http.NewRequest(http.MethodGet, "/user/60ecb2bf67774900350d9c43/load_balancer", nil)
+	updateInputToSend, err = json.Marshal(rawUpdateInput)
 	c.NoError(err)
 
-	rr := httptest.NewRecorder()
-
-	router, err := newTestRouter()
+	req, err = http.NewRequest(http.MethodPost, "/application/first_date_surpassed", bytes.NewBuffer(updateInputToSend))
 	c.NoError(err)
 
+	rr = httptest.NewRecorder()
+
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusOK, rr.Code)
+	c.Equal(http.StatusNotFound, rr.Code)
 
-	var marshaledBody []*repository.LoadBalancer
+	rawUpdateInput.ApplicationIDs = nil
 
-	err = json.Unmarshal(rr.Body.Bytes(), &marshaledBody)
+	updateInputToSend, err = json.Marshal(rawUpdateInput)
 	c.NoError(err)
 
-	c.Equal("60ecb2bf67774900350d9c42", marshaledBody[0].ID)
-
-	req, err = http.NewRequest(http.MethodGet, "/user/60ecb2bf67774900350d9d43/load_balancer", nil)
+	req, err = http.NewRequest(http.MethodPost, "/application/first_date_surpassed", bytes.NewBuffer(updateInputToSend))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
 
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusNotFound, rr.Code)
+	c.Equal(http.StatusBadRequest, rr.Code)
 }
 
-func TestRouter_GetBlockchains(t *testing.T) {
+func TestRouter_BulkUpdateApplications(t *testing.T) {
 	c := require.New(t)
 
-	req, err := http.NewRequest(http.MethodGet, "/blockchain", nil)
+	router, err := newTestRouter()
 	c.NoError(err)
 
-	rr := httptest.NewRecorder()
+	writerMock := &writerMock{}
 
-	router, err := newTestRouter()
-	c.NoError(err)
+	router.Writer = writerMock
 
-	router.Router.ServeHTTP(rr, req)
+	post := func(body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, "/application/bulk_update", bytes.NewBufferString(body))
+		c.NoError(err)
+
+		rr := httptest.NewRecorder()
 
+		router.Router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := post(`{"filter":{"payPlan":"FREETIER_V0"},"update":{"appLimit":{"payPlan":{"planType":"PAY_AS_YOU_GO_V0"}}},"dryRun":true}`)
 	c.Equal(http.StatusOK, rr.Code)
 
-	expectedBody, err := json.Marshal([]*repository.Blockchain{
-		{
-			ID: "0021",
-			Redirects: []repository.Redirect{
-				{
-					BlockchainID:   "0021",
-					Alias:          "pokt-mainnet",
-					Domain:         "pokt-mainnet.gateway.network",
-					LoadBalancerID: "12345",
-				},
-			},
-		},
-		{
-			ID: "0022",
-			Redirects: []repository.Redirect{
-				{
-					BlockchainID:   "0022",
-					Alias:          "eth-mainnet",
-					Domain:         "eth-mainnet.gateway.network",
-					LoadBalancerID: "45678",
-				},
-			},
-		},
-	})
-	c.NoError(err)
+	var result types.BulkUpdateResult
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &result))
+	c.True(result.DryRun)
+	c.Len(result.Updated, 1)
+	c.Equal("5f62b7d8be3591c4dea8566d", result.Updated[0].ApplicationID)
+	c.Len(result.Updated[0].Changes, 1)
+	c.JSONEq(`{"payPlan":{"planType":"FREETIER_V0","dailyLimit":250000},"customLimit":0}`, string(result.Updated[0].Changes["limit"].From))
+	c.JSONEq(`{"payPlan":{"planType":"PAY_AS_YOU_GO_V0","dailyLimit":0},"customLimit":0}`, string(result.Updated[0].Changes["limit"].To))
+	c.Empty(result.Unchanged)
+	c.Empty(result.Missing)
+
+	var written []*repository.Application
+
+	writerMock.On("PatchApplications", mock.MatchedBy(func(apps []*repository.Application) bool {
+		written = apps
+		return true
+	})).Return(nil).Once()
+
+	rr = post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d","5f62b7d8be3591c4dea8566a","5f62b7d8be3591c4dea8566a",
+		"5f62b7d8be3591c4dea85664"],"update":{"status":"IN_SERVICE"}}`)
+	c.Equal(http.StatusOK, rr.Code)
 
-	c.Equal(expectedBody, rr.Body.Bytes())
-}
+	c.Len(written, 2)
+	c.Equal("5f62b7d8be3591c4dea8566d", written[0].ID)
+	c.Equal(repository.InService, written[0].Status)
+	c.Equal(repository.FreetierV0, written[0].Limit.PayPlan.Type)
+	c.Equal("5f62b7d8be3591c4dea8566a", written[1].ID)
+	c.Equal(repository.InService, written[1].Status)
 
-func TestRouter_GetBlockchain(t *testing.T) {
-	c := require.New(t)
+	result = types.BulkUpdateResult{}
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &result))
+	c.False(result.DryRun)
+	c.Len(result.Updated, 2)
+	c.Equal(json.RawMessage(`"IN_SERVICE"`), result.Updated[1].Changes["status"].To)
+	c.Equal([]string{"5f62b7d8be3591c4dea85664"}, result.Missing)
 
-	req, err := http.NewRequest(http.MethodGet, "/blockchain/0021", nil)
-	c.NoError(err)
+	// responses are copies, the cache is only updated by the database notification
+	c.Empty(router.Cache.GetApplication("5f62b7d8be3591c4dea8566a").Status)
 
-	rr := httptest.NewRecorder()
+	rr = post(`{"filter":{"userID":"60ecb2bf67774900350d9c44"},"update":{"notificationSettings":{}}}`)
+	c.Equal(http.StatusOK, rr.Code)
 
-	router, err := newTestRouter()
-	c.NoError(err)
+	result = types.BulkUpdateResult{}
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &result))
+	c.Empty(result.Updated)
+	c.Equal([]string{"5f62b7d8be3591c4dea8566f"}, result.Unchanged)
 
-	router.Router.ServeHTTP(rr, req)
+	// the gateway settings of each application are kept but whether the secret key is required, and
+	// callers that cannot read secrets get none back in the changes
+	app := router.Cache.GetApplication("5f62b7d8be3591c4dea8566d")
+	app.GatewaySettings.SecretKey = "secret_key"
+	app.GatewaySettings.WhitelistOrigins = []string{"https://pokt.network"}
 
+	rr = post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d"],"update":{"gatewaySettings":{"secretKeyRequired":true}},"dryRun":true}`)
 	c.Equal(http.StatusOK, rr.Code)
+	c.NotContains(rr.Body.String(), "secret_key")
 
-	expectedBody, err := json.Marshal(&repository.Blockchain{
-		ID: "0021",
-		Redirects: []repository.Redirect{
-			{
-				BlockchainID:   "0021",
-				Alias:          "pokt-mainnet",
-				Domain:         "pokt-mainnet.gateway.network",
-				LoadBalancerID: "12345",
-			},
-		},
-	})
-	c.NoError(err)
-
-	c.Equal(expectedBody, rr.Body.Bytes())
+	result = types.BulkUpdateResult{}
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &result))
+	c.Len(result.Updated, 1)
 
-	req, err = http.NewRequest(http.MethodGet, "/blockchain/00210", nil)
-	c.NoError(err)
+	var settingsTo repository.GatewaySettings
+	c.NoError(json.Unmarshal(result.Updated[0].Changes["gatewaySettings"].To, &settingsTo))
+	c.True(settingsTo.SecretKeyRequired)
+	c.Empty(settingsTo.SecretKey)
+	c.Equal([]string{"https://pokt.network"}, settingsTo.WhitelistOrigins)
 
-	rr = httptest.NewRecorder()
+	router.Capabilities = map[string]map[Capability]bool{"": {CapabilitySecretsRead: true}}
 
-	router.Router.ServeHTTP(rr, req)
+	rr = post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d"],"update":{"gatewaySettings":{"secretKeyRequired":true}},"dryRun":true}`)
+	c.Equal(http.StatusOK, rr.Code)
+	c.Contains(rr.Body.String(), "secret_key")
 
-	c.Equal(http.StatusNotFound, rr.Code)
-}
+	router.Capabilities = nil
 
-func TestRouter_GetLoadBalancers(t *testing.T) {
-	c := require.New(t)
+	c.Equal(http.StatusBadRequest, post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d"],"update":{"gatewaySettings":{"secretKey":"1234"}}}`).Code)
+	c.Equal(http.StatusBadRequest, post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d"],
+		"update":{"gatewaySettings":{"whitelistOrigins":["https://pokt.network"]}}}`).Code)
+	c.Equal(http.StatusBadRequest, post(`wrong`).Code)
+	c.Equal(http.StatusBadRequest, post(`{"update":{"status":"IN_SERVICE"}}`).Code)
+	c.Equal(http.StatusBadRequest, post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d"],"filter":{"status":"READY"},"update":{"status":"IN_SERVICE"}}`).Code)
+	c.Equal(http.StatusBadRequest, post(`{"filter":{},"update":{"status":"IN_SERVICE"}}`).Code)
+	c.Equal(http.StatusBadRequest, post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d"],"update":{}}`).Code)
+	c.Equal(http.StatusBadRequest, post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d"],"update":{"name":"pablo"}}`).Code)
+	c.Equal(http.StatusBadRequest, post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d"],"update":{"remove":true}}`).Code)
+	c.Equal(http.StatusBadRequest, post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d"],"update":{"status":"WRONG"}}`).Code)
+	c.Equal(http.StatusBadRequest, post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d"],
+		"update":{"gatewaySettings":{"whitelistMethods":[{"blockchainID":"0021","methods":["eth call"]}]}}}`).Code)
 
-	req, err := http.NewRequest(http.MethodGet, "/load_balancer", nil)
-	c.NoError(err)
+	writerMock.On("PatchApplications", mock.Anything).Return(fmt.Errorf("%w: 5f62b7d8be3591c4dea8566d", types.ErrApplicationChanged)).Once()
 
-	rr := httptest.NewRecorder()
+	c.Equal(http.StatusConflict, post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d"],"update":{"status":"IN_SERVICE"}}`).Code)
 
-	router, err := newTestRouter()
-	c.NoError(err)
+	writerMock.On("PatchApplications", mock.Anything).Return(errors.New("dummy error")).Once()
 
-	router.Router.ServeHTTP(rr, req)
+	c.Equal(http.StatusUnprocessableEntity, post(`{"applicationIDs":["5f62b7d8be3591c4dea8566d"],"update":{"status":"IN_SERVICE"}}`).Code)
 
-	c.Equal(http.StatusOK, rr.Code)
+	writerMock.AssertExpectations(t)
+}
 
-	var marshaledBody []*repository.LoadBalancer
+func TestRouter_UnknownPayPlan(t *testing.T) {
+	c := require.New(t)
 
-	err = json.Unmarshal(rr.Body.Bytes(), &marshaledBody)
+	router, err := newTestRouter()
 	c.NoError(err)
 
-	c.Len(marshaledBody, 2)
-	c.Equal("60ecb2bf67774900350d9c42", marshaledBody[0].ID)
-	c.Equal("60ecb2bf67774900350d9c43", marshaledBody[1].ID)
-}
+	writerMock := &writerMock{}
 
-func TestRouter_GetLoadBalancer(t *testing.T) {
-	c := require.New(t)
+	router.Writer = writerMock
 
-	req, err := http.NewRequest(http.MethodGet, "/load_balancer/60ecb2bf67774900350d9c42", nil)
-	c.NoError(err)
+	requests := []struct {
+		method, path, body string
+	}{
+		{http.MethodPost, "/application", `{"userID":"60ecb2bf67774900350d9c43","limit":{"payPlan":{"planType":"TEST_PLAN_V0"}}}`},
+		{http.MethodPut, "/application/5f62b7d8be3591c4dea8566d", `{"appLimit":{"payPlan":{"planType":"TEST_PLAN_V0"}}}`},
+		{http.MethodPatch, "/application/5f62b7d8be3591c4dea8566d", `{"limit":{"payPlan":{"planType":"TEST_PLAN_V0"}}}`},
+		{http.MethodPost, "/application/bulk_update", `{"applicationIDs":["5f62b7d8be3591c4dea8566d"],"update":{"appLimit":{"payPlan":{"planType":"TEST_PLAN_V0"}}}}`},
+	}
 
-	rr := httptest.NewRecorder()
+	for _, request := range requests {
+		req, err := http.NewRequest(request.method, request.path, bytes.NewBufferString(request.body))
+		c.NoError(err)
 
-	router, err := newTestRouter()
-	c.NoError(err)
+		if request.method == http.MethodPatch {
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+		}
 
-	router.Router.ServeHTTP(rr, req)
+		rr := httptest.NewRecorder()
 
-	c.Equal(http.StatusOK, rr.Code)
+		router.Router.ServeHTTP(rr, req)
 
-	var marshaledBody repository.LoadBalancer
+		c.Equal(http.StatusUnprocessableEntity, rr.Code, request.path)
+		c.Contains(rr.Body.String(), errNoPayFound.Error())
+	}
 
-	err = json.Unmarshal(rr.Body.Bytes(), &marshaledBody)
-	c.NoError(err)
+	writerMock.AssertExpectations(t)
+}
 
-	c.Equal("60ecb2bf67774900350d9c42", marshaledBody.ID)
+func TestRouter_UpdateUsageStates(t *testing.T) {
+	c := require.New(t)
 
-	req, err = http.NewRequest(http.MethodGet, "/load_balancer/60fcb2bf67774900350d9c42", nil)
+	router, err := newTestRouter()
 	c.NoError(err)
 
-	rr = httptest.NewRecorder()
+	writerMock := &writerMock{}
 
-	router.Router.ServeHTTP(rr, req)
+	router.Writer = writerMock
 
-	c.Equal(http.StatusNotFound, rr.Code)
-}
+	post := func(body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, "/application/usage_state", bytes.NewBufferString(body))
+		c.NoError(err)
 
-func TestRouter_CreateLoadBalancer(t *testing.T) {
-	c := require.New(t)
+		rr := httptest.NewRecorder()
 
-	rawLBToSend := &repository.LoadBalancer{
-		UserID: "60ddc61b6e29c3003378361D",
+		router.Router.ServeHTTP(rr, req)
+
+		return rr
 	}
 
-	lbToSend, err := json.Marshal(rawLBToSend)
-	c.NoError(err)
+	firstDateSurpassed := time.Date(2022, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	writerMock.On("UpdateUsageStates", mock.MatchedBy(func(updates []*types.UsageStateUpdate) bool {
+		return len(updates) == 3 && updates[0].ApplicationID == "5f62b7d8be3591c4dea8566d" &&
+			updates[1].ApplicationID == "5f62b7d8be3591c4dea8566a" && updates[2].ApplicationID == "5f62b7d8be3591c4dea8566f"
+	})).Return([]*types.UsageState{
+		{ApplicationID: "5f62b7d8be3591c4dea8566d", FirstDateSurpassed: firstDateSurpassed, ThresholdsReached: []types.UsageThreshold{}},
+		{ApplicationID: "5f62b7d8be3591c4dea8566a", ThresholdsReached: []types.UsageThreshold{types.ThresholdHalf, types.ThresholdQuarter}},
+		nil,
+	}, []error{nil, nil, types.ErrApplicationNotFound}, nil).Once()
+
+	rr := post(`{"updates":[
+		{"applicationID":"5f62b7d8be3591c4dea8566d","firstDateSurpassed":"2022-08-01T00:00:00Z"},
+		{"applicationID":"5f62b7d8be3591c4dea85664","reset":true},
+		{"applicationID":"5f62b7d8be3591c4dea8566a","thresholdsReached":["quarter","half"]},
+		{"applicationID":"5f62b7d8be3591c4dea8566a","thresholdsReached":["eighth"]},
+		{"applicationID":"5f62b7d8be3591c4dea8566a","reset":true,"thresholdsReached":["full"]},
+		{"applicationID":"5f62b7d8be3591c4dea8566a"},
+		{"reset":true},
+		{"applicationID":"5f62b7d8be3591c4dea8566f","reset":true}
+	]}`)
+	c.Equal(http.StatusOK, rr.Code)
 
-	req, err := http.NewRequest(http.MethodPost, "/load_balancer", bytes.NewBuffer(lbToSend))
-	c.NoError(err)
+	var results []types.UsageStateResult
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &results))
+	c.Len(results, 8)
+
+	c.Equal(http.StatusOK, results[0].Status)
+	c.Equal(firstDateSurpassed, results[0].UsageState.FirstDateSurpassed)
+	c.Equal(http.StatusNotFound, results[1].Status)
+	c.Equal("5f62b7d8be3591c4dea85664", results[1].ApplicationID)
+	c.Equal(http.StatusOK, results[2].Status)
+	c.Equal([]types.UsageThreshold{types.ThresholdHalf, types.ThresholdQuarter}, results[2].UsageState.ThresholdsReached)
+	c.Equal(http.StatusBadRequest, results[3].Status)
+	c.Contains(results[3].Error, types.ErrInvalidUsageThreshold.Error())
+	c.Equal(http.StatusBadRequest, results[4].Status)
+	c.Equal(types.ErrResetWithUsageState.Error(), results[4].Error)
+	c.Equal(http.StatusBadRequest, results[5].Status)
+	c.Equal(types.ErrNoUsageStateToUpdate.Error(), results[5].Error)
+	c.Equal(http.StatusBadRequest, results[6].Status)
+	c.Equal(types.ErrMissingApplicationID.Error(), results[6].Error)
+	// missing from the database even if still cached
+	c.Equal(http.StatusNotFound, results[7].Status)
+	c.Nil(results[7].UsageState)
+
+	// a failed update only fails its own result
+	writerMock.On("UpdateUsageStates", mock.Anything).Return([]*types.UsageState{
+		nil,
+		{ApplicationID: "5f62b7d8be3591c4dea8566a", ThresholdsReached: []types.UsageThreshold{}},
+	}, []error{errors.New("dummy error"), nil}, nil).Once()
+
+	results = nil
+	c.NoError(json.Unmarshal(post(`{"updates":[
+		{"applicationID":"5f62b7d8be3591c4dea8566d","reset":true},
+		{"applicationID":"5f62b7d8be3591c4dea8566a","reset":true}
+	]}`).Body.Bytes(), &results))
+	c.Equal(http.StatusInternalServerError, results[0].Status)
+	c.Equal("dummy error", results[0].Error)
+	c.Nil(results[0].UsageState)
+	c.Equal(http.StatusOK, results[1].Status)
+
+	results = nil
+	c.NoError(json.Unmarshal(post(`{"updates":[{"applicationID":"5f62b7d8be3591c4dea85664","reset":true}]}`).Body.Bytes(), &results))
+	c.Equal(http.StatusNotFound, results[0].Status)
+
+	c.Equal(http.StatusBadRequest, post(`wrong`).Code)
+	c.Equal(http.StatusBadRequest, post(`{"updates":[]}`).Code)
+
+	writerMock.On("UpdateUsageStates", mock.Anything).Return([]*types.UsageState(nil), []error(nil), errors.New("dummy error")).Once()
+
+	c.Equal(http.StatusInternalServerError, post(`{"updates":[{"applicationID":"5f62b7d8be3591c4dea8566d","reset":true}]}`).Code)
+
+	writerMock.AssertExpectations(t)
+}
 
-	rr := httptest.NewRecorder()
+func TestRouter_GetUsageState(t *testing.T) {
+	c := require.New(t)
 
 	router, err := newTestRouter()
 	c.NoError(err)
 
-	lbToReturn := &repository.LoadBalancer{
-		ID:     "60ddc61b6e29c3003378361E",
-		UserID: "60ddc61b6e29c3003378361D",
-	}
-
 	writerMock := &writerMock{}
 
-	writerMock.On("WriteLoadBalancer", mock.Anything).Return(lbToReturn, nil).Once()
-
 	router.Writer = writerMock
 
-	router.Router.ServeHTTP(rr, req)
-
-	c.Equal(http.StatusOK, rr.Code)
-
-	marshaledReturnLB, err := json.Marshal(lbToReturn)
-	c.NoError(err)
+	get := func(path string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		c.NoError(err)
 
-	c.Equal(marshaledReturnLB, rr.Body.Bytes())
+		rr := httptest.NewRecorder()
 
-	req, err = http.NewRequest(http.MethodPost, "/load_balancer", bytes.NewBuffer([]byte("wrong")))
-	c.NoError(err)
+		router.Router.ServeHTTP(rr, req)
 
-	rr = httptest.NewRecorder()
+		return rr
+	}
 
-	router.Router.ServeHTTP(rr, req)
+	writerMock.On("ReadUsageState", "5f62b7d8be3591c4dea8566d").Return(&types.UsageState{
+		ApplicationID:     "5f62b7d8be3591c4dea8566d",
+		ThresholdsReached: []types.UsageThreshold{types.ThresholdFull},
+	}, nil).Once()
 
-	c.Equal(http.StatusBadRequest, rr.Code)
+	rr := get("/application/5f62b7d8be3591c4dea8566d/usage_state")
+	c.Equal(http.StatusOK, rr.Code)
 
-	req, err = http.NewRequest(http.MethodPost, "/load_balancer", bytes.NewBuffer(lbToSend))
-	c.NoError(err)
+	var state types.UsageState
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &state))
+	c.Equal([]types.UsageThreshold{types.ThresholdFull}, state.ThresholdsReached)
 
-	rr = httptest.NewRecorder()
+	c.Equal(http.StatusNotFound, get("/application/5f62b7d8be3591c4dea85664/usage_state").Code)
 
-	writerMock.On("WriteLoadBalancer", mock.Anything).Return(lbToReturn, errors.New("dummy error")).Once()
+	writerMock.On("ReadUsageState", "5f62b7d8be3591c4dea8566d").Return((*types.UsageState)(nil), errors.New("dummy error")).Once()
 
-	router.Router.ServeHTTP(rr, req)
+	c.Equal(http.StatusInternalServerError, get("/application/5f62b7d8be3591c4dea8566d/usage_state").Code)
 
-	c.Equal(http.StatusInternalServerError, rr.Code)
+	writerMock.AssertExpectations(t)
 }
 
-func TestRouter_UpdateLoadBalancer(t *testing.T) {
+func TestRouter_RemoveApplication(t *testing.T) {
 	c := require.New(t)
 
-	rawUpdateInput := &repository.UpdateLoadBalancer{
-		Name: "pablo",
-		StickyOptions: &repository.StickyOptions{
-			Duration:      "21",
-			StickyOrigins: []string{"pjog"},
-			StickyMax:     21,
-			Stickiness:    true,
-		},
+	rawRemoveInput := &repository.UpdateApplication{
+		Remove: true,
 	}
 
-	updateInputToSend, err := json.Marshal(rawUpdateInput)
+	updateInputToSend, err := json.Marshal(rawRemoveInput)
 	c.NoError(err)
 
-	req, err := http.NewRequest(http.MethodPut, "/load_balancer/60ecb2bf67774900350d9c42", bytes.NewBuffer(updateInputToSend))
+	req, err := http.NewRequest(http.MethodPut, "/application/5f62b7d8be3591c4dea8566d", bytes.NewBuffer(updateInputToSend))
 	c.NoError(err)
 
 	rr := httptest.NewRecorder()
@@ -902,7 +1291,7 @@ func TestRouter_UpdateLoadBalancer(t *testing.T) {
 
 	writerMock := &writerMock{}
 
-	writerMock.On("UpdateLoadBalancer", mock.Anything).Return(nil).Once()
+	writerMock.On("RemoveApplication", mock.Anything).Return(time.Time{}, nil).Once()
 
 	router.Writer = writerMock
 
@@ -910,7 +1299,7 @@ func TestRouter_UpdateLoadBalancer(t *testing.T) {
 
 	c.Equal(http.StatusOK, rr.Code)
 
-	req, err = http.NewRequest(http.MethodPut, "/load_balancer/5f62b7d8be3591c4dea85664", bytes.NewBuffer(updateInputToSend))
+	req, err = http.NewRequest(http.MethodPut, "/application/5f62b7d8be3591c4dea85664", bytes.NewBuffer(updateInputToSend))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
@@ -919,7 +1308,7 @@ func TestRouter_UpdateLoadBalancer(t *testing.T) {
 
 	c.Equal(http.StatusNotFound, rr.Code)
 
-	req, err = http.NewRequest(http.MethodPut, "/load_balancer/60ecb2bf67774900350d9c42", bytes.NewBuffer([]byte("wrong")))
+	req, err = http.NewRequest(http.MethodPut, "/application/5f62b7d8be3591c4dea8566d", bytes.NewBuffer([]byte("wrong")))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
@@ -928,58 +1317,87 @@ func TestRouter_UpdateLoadBalancer(t *testing.T) {
 
 	c.Equal(http.StatusBadRequest, rr.Code)
 
-	req, err = http.NewRequest(http.MethodPut, "/load_balancer/60ecb2bf67774900350d9c42", bytes.NewBuffer(updateInputToSend))
+	req, err = http.NewRequest(http.MethodPut, "/application/5f62b7d8be3591c4dea8566d", bytes.NewBuffer(updateInputToSend))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
 
-	writerMock.On("UpdateLoadBalancer", mock.Anything).Return(errors.New("dummy error")).Once()
+	writerMock.On("RemoveApplication", mock.Anything).Return(time.Time{}, errors.New("dummy error")).Once()
 
 	router.Router.ServeHTTP(rr, req)
 
 	c.Equal(http.StatusInternalServerError, rr.Code)
 }
 
-func TestRouter_RemoveLoadBalancer(t *testing.T) {
+func TestRouter_GetApplicationsByUserID(t *testing.T) {
 	c := require.New(t)
 
-	rawUpdateInput := &repository.UpdateLoadBalancer{
-		Remove: true,
-	}
-
-	updateInputToSend, err := json.Marshal(rawUpdateInput)
+	req, err := http.NewRequest(http.MethodGet, "/user/60ecb2bf67774900350d9c43/application", nil)
 	c.NoError(err)
 
+	rr := httptest.NewRecorder()
+
 	router, err := newTestRouter()
 	c.NoError(err)
 
-	writerMock := &writerMock{}
+	router.Router.ServeHTTP(rr, req)
 
-	router.Writer = writerMock
+	c.Equal(http.StatusOK, rr.Code)
 
-	req, err := http.NewRequest(http.MethodPut, "/load_balancer/60ecb2bf67774900350d9c42", bytes.NewBuffer(updateInputToSend))
+	expectedBody, err := json.Marshal([]*repository.Application{
+		{
+			ID:     "5f62b7d8be3591c4dea8566d",
+			UserID: "60ecb2bf67774900350d9c43",
+			Limit: repository.AppLimit{
+				PayPlan: repository.PayPlan{
+					Type:  repository.FreetierV0,
+					Limit: 250000,
+				},
+			},
+			FirstDateSurpassed: time.Date(2022, time.July, 21, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:     "5f62b7d8be3591c4dea8566a",
+			UserID: "60ecb2bf67774900350d9c43",
+		},
+	})
 	c.NoError(err)
 
-	rr := httptest.NewRecorder()
+	c.Equal(expectedBody, rr.Body.Bytes())
+
+	req, err = http.NewRequest(http.MethodGet, "/user/61ecb2bf67774900350d9c43/application", nil)
+	c.NoError(err)
 
-	writerMock.On("RemoveLoadBalancer", mock.Anything).Return(errors.New("dummy error")).Once()
+	rr = httptest.NewRecorder()
 
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusInternalServerError, rr.Code)
+	c.Equal(http.StatusNotFound, rr.Code)
+}
 
-	req, err = http.NewRequest(http.MethodPut, "/load_balancer/60ecb2bf67774900350d9c42", bytes.NewBuffer(updateInputToSend))
+func TestRouter_GetLoadbalancerByUserID(t *testing.T) {
+	c := require.New(t)
+
+	req, err := http.NewRequest(http.MethodGet, "/user/60ecb2bf67774900350d9c43/load_balancer", nil)
 	c.NoError(err)
 
-	rr = httptest.NewRecorder()
+	rr := httptest.NewRecorder()
 
-	writerMock.On("RemoveLoadBalancer", mock.Anything).Return(nil).Once()
+	router, err := newTestRouter()
+	c.NoError(err)
 
 	router.Router.ServeHTTP(rr, req)
 
 	c.Equal(http.StatusOK, rr.Code)
 
-	req, err = http.NewRequest(http.MethodPut, "/load_balancer/5f62b7d8be3591c4dea85664", bytes.NewBuffer(updateInputToSend))
+	var marshaledBody []*repository.LoadBalancer
+
+	err = json.Unmarshal(rr.Body.Bytes(), &marshaledBody)
+	c.NoError(err)
+
+	c.Equal("60ecb2bf67774900350d9c42", marshaledBody[0].ID)
+
+	req, err = http.NewRequest(http.MethodGet, "/user/60ecb2bf67774900350d9d43/load_balancer", nil)
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
@@ -989,60 +1407,88 @@ func TestRouter_RemoveLoadBalancer(t *testing.T) {
 	c.Equal(http.StatusNotFound, rr.Code)
 }
 
-func TestRouter_GetPayPlans(t *testing.T) {
+func TestRouter_GetUsers(t *testing.T) {
 	c := require.New(t)
 
-	req, err := http.NewRequest(http.MethodGet, "/pay_plan", nil)
+	router, err := newTestRouter()
 	c.NoError(err)
 
-	rr := httptest.NewRecorder()
-
-	router, err := newTestRouter()
+	req, err := http.NewRequest(http.MethodGet, "/user", nil)
 	c.NoError(err)
 
+	rr := httptest.NewRecorder()
+
 	router.Router.ServeHTTP(rr, req)
 
 	c.Equal(http.StatusOK, rr.Code)
 
-	expectedBody, err := json.Marshal([]*repository.PayPlan{
-		{
-			Type:  repository.FreetierV0,
-			Limit: 250000,
-		},
-		{
-			Type:  repository.PayAsYouGoV0,
-			Limit: 0,
-		},
-	})
-	c.NoError(err)
+	var summaries []types.UserSummary
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &summaries))
+	c.Len(summaries, 2)
 
-	c.Equal(expectedBody, rr.Body.Bytes())
+	c.Equal("60ecb2bf67774900350d9c43", summaries[0].UserID)
+	c.Equal(2, summaries[0].ApplicationsCount)
+	c.Equal(1, summaries[0].LoadBalancersCount)
+	c.Equal(1, summaries[0].ApplicationsByPayPlan[repository.FreetierV0])
+	c.Equal(250000, summaries[0].TotalDailyLimit)
+
+	c.Equal("60ecb2bf67774900350d9c44", summaries[1].UserID)
+	c.Equal(1, summaries[1].ApplicationsCount)
+	c.Equal(0, summaries[1].LoadBalancersCount)
 }
 
-func TestRouter_GetPayPlan(t *testing.T) {
+func TestRouter_GetUser(t *testing.T) {
 	c := require.New(t)
 
-	req, err := http.NewRequest(http.MethodGet, "/pay_plan/freetier_v0", nil)
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	req, err := http.NewRequest(http.MethodGet, "/user/60ecb2bf67774900350d9c43", nil)
 	c.NoError(err)
 
 	rr := httptest.NewRecorder()
 
-	router, err := newTestRouter()
-	c.NoError(err)
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var user types.User
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &user))
+	c.Len(user.Applications, 2)
+	c.Len(user.LoadBalancers, 1)
+	c.Equal(2, user.Summary.ApplicationsCount)
+	c.Equal(250000, user.Summary.TotalDailyLimit)
+
+	// Applications awaiting grace period do not add to the daily limit
+	router.Cache.GetApplication("5f62b7d8be3591c4dea8566d").Status = repository.AwaitingGracePeriod
+
+	rr = httptest.NewRecorder()
 
 	router.Router.ServeHTTP(rr, req)
 
 	c.Equal(http.StatusOK, rr.Code)
 
-	expectedBody, err := json.Marshal(&repository.PayPlan{
-		Type:  repository.FreetierV0,
-		Limit: 250000,
-	})
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &user))
+	c.Equal(1, user.Summary.ApplicationsByStatus[repository.AwaitingGracePeriod])
+	c.Equal(0, user.Summary.TotalDailyLimit)
+
+	// User without load balancers
+	req, err = http.NewRequest(http.MethodGet, "/user/60ecb2bf67774900350d9c44", nil)
 	c.NoError(err)
 
-	c.Equal(expectedBody, rr.Body.Bytes())
+	rr = httptest.NewRecorder()
 
-	req, err = http.NewRequest(http.MethodGet, "/pay_plan/freetier_v21", nil)
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	user = types.User{}
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &user))
+	c.Len(user.Applications, 1)
+	c.NotNil(user.LoadBalancers)
+	c.Empty(user.LoadBalancers)
+
+	req, err = http.NewRequest(http.MethodGet, "/user/60ecb2bf67774900350d9c99", nil)
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
@@ -1052,17 +1498,54 @@ func TestRouter_GetPayPlan(t *testing.T) {
 	c.Equal(http.StatusNotFound, rr.Code)
 }
 
-func TestRouter_CreateBlockchain(t *testing.T) {
+func TestRouter_GetBlockchains(t *testing.T) {
 	c := require.New(t)
 
-	rawChainToSend := &repository.Blockchain{
-		Ticker: "POKT",
-	}
+	req, err := http.NewRequest(http.MethodGet, "/blockchain", nil)
+	c.NoError(err)
 
-	chainToSend, err := json.Marshal(rawChainToSend)
+	rr := httptest.NewRecorder()
+
+	router, err := newTestRouter()
 	c.NoError(err)
 
-	req, err := http.NewRequest(http.MethodPost, "/blockchain", bytes.NewBuffer(chainToSend))
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	expectedBody, err := json.Marshal([]*repository.Blockchain{
+		{
+			ID: "0021",
+			Redirects: []repository.Redirect{
+				{
+					BlockchainID:   "0021",
+					Alias:          "pokt-mainnet",
+					Domain:         "pokt-mainnet.gateway.network",
+					LoadBalancerID: "12345",
+				},
+			},
+		},
+		{
+			ID: "0022",
+			Redirects: []repository.Redirect{
+				{
+					BlockchainID:   "0022",
+					Alias:          "eth-mainnet",
+					Domain:         "eth-mainnet.gateway.network",
+					LoadBalancerID: "45678",
+				},
+			},
+		},
+	})
+	c.NoError(err)
+
+	c.Equal(expectedBody, rr.Body.Bytes())
+}
+
+func TestRouter_GetBlockchain(t *testing.T) {
+	c := require.New(t)
+
+	req, err := http.NewRequest(http.MethodGet, "/blockchain/0021", nil)
 	c.NoError(err)
 
 	rr := httptest.NewRecorder()
@@ -1070,56 +1553,148 @@ func TestRouter_CreateBlockchain(t *testing.T) {
 	router, err := newTestRouter()
 	c.NoError(err)
 
-	chainToReturn := &repository.Blockchain{
-		ID:     "60ddc61b6e29c3003378361E",
-		Ticker: "POKT",
-	}
+	router.Router.ServeHTTP(rr, req)
 
-	writerMock := &writerMock{}
+	c.Equal(http.StatusOK, rr.Code)
 
-	writerMock.On("WriteBlockchain", mock.Anything).Return(chainToReturn, nil).Once()
+	expectedBody, err := json.Marshal(&repository.Blockchain{
+		ID: "0021",
+		Redirects: []repository.Redirect{
+			{
+				BlockchainID:   "0021",
+				Alias:          "pokt-mainnet",
+				Domain:         "pokt-mainnet.gateway.network",
+				LoadBalancerID: "12345",
+			},
+		},
+	})
+	c.NoError(err)
 
-	router.Writer = writerMock
+	c.Equal(expectedBody, rr.Body.Bytes())
+
+	req, err = http.NewRequest(http.MethodGet, "/blockchain/00210", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
 
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusOK, rr.Code)
+	c.Equal(http.StatusNotFound, rr.Code)
+}
 
-	marshaledReturnChain, err := json.Marshal(chainToReturn)
+func TestRouter_GetBlockchainByAlias(t *testing.T) {
+	c := require.New(t)
+
+	req, err := http.NewRequest(http.MethodGet, "/blockchain/alias/POKT-mainnet", nil)
 	c.NoError(err)
 
-	c.Equal(marshaledReturnChain, rr.Body.Bytes())
+	rr := httptest.NewRecorder()
 
-	req, err = http.NewRequest(http.MethodPost, "/blockchain", bytes.NewBuffer([]byte("wrong")))
+	router, err := newTestRouterWithBlockchains([]*repository.Blockchain{
+		{
+			ID:                "0021",
+			BlockchainAliases: []string{"pokt-mainnet"},
+		},
+		{
+			ID: "0022",
+		},
+	})
+	c.NoError(err)
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var blockchain repository.Blockchain
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &blockchain))
+	c.Equal("0021", blockchain.ID)
+
+	req, err = http.NewRequest(http.MethodGet, "/blockchain/alias/eth-mainnet", nil)
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
 
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusBadRequest, rr.Code)
+	c.Equal(http.StatusNotFound, rr.Code)
+}
 
-	req, err = http.NewRequest(http.MethodPost, "/blockchain", bytes.NewBuffer(chainToSend))
+func TestRouter_GetLoadBalancers(t *testing.T) {
+	c := require.New(t)
+
+	req, err := http.NewRequest(http.MethodGet, "/load_balancer", nil)
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var marshaledBody []*types.LoadBalancer
+
+	err = json.Unmarshal(rr.Body.Bytes(), &marshaledBody)
+	c.NoError(err)
+
+	c.Len(marshaledBody, 1)
+	c.Equal("60ecb2bf67774900350d9c42", marshaledBody[0].ID)
+	c.Nil(marshaledBody[0].RemovedAt)
+
+	req, err = http.NewRequest(http.MethodGet, "/load_balancer?include_removed=true", nil)
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
 
-	writerMock.On("WriteBlockchain", mock.Anything).Return(chainToReturn, errors.New("dummy error")).Once()
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	err = json.Unmarshal(rr.Body.Bytes(), &marshaledBody)
+	c.NoError(err)
+
+	c.Len(marshaledBody, 2)
+	c.Equal("60ecb2bf67774900350d9c42", marshaledBody[0].ID)
+	c.Nil(marshaledBody[0].RemovedAt)
+	c.Equal("60ecb2bf67774900350d9c43", marshaledBody[1].ID)
+	c.Equal(time.Date(2022, time.July, 21, 0, 0, 0, 0, time.UTC), *marshaledBody[1].RemovedAt)
+
+	// Load balancer without owner is not removed unless its removal is recorded
+	router.Cache.GetLoadBalancer("60ecb2bf67774900350d9c42").UserID = ""
+
+	req, err = http.NewRequest(http.MethodGet, "/load_balancer", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
 
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusInternalServerError, rr.Code)
-}
+	c.Equal(http.StatusOK, rr.Code)
 
-func TestRouter_CreateRedirect(t *testing.T) {
-	c := require.New(t)
+	marshaledBody = nil
 
-	rawRedirectsToSend := &repository.Redirect{BlockchainID: "0021"}
+	err = json.Unmarshal(rr.Body.Bytes(), &marshaledBody)
+	c.NoError(err)
 
-	redirectToSend, err := json.Marshal(rawRedirectsToSend)
+	c.Len(marshaledBody, 1)
+	c.Equal("60ecb2bf67774900350d9c42", marshaledBody[0].ID)
+
+	req, err = http.NewRequest(http.MethodGet, "/load_balancer?include_removed=maybe", nil)
 	c.NoError(err)
 
-	req, err := http.NewRequest(http.MethodPost, "/redirect", bytes.NewBuffer(redirectToSend))
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func TestRouter_GetLoadBalancer(t *testing.T) {
+	c := require.New(t)
+
+	req, err := http.NewRequest(http.MethodGet, "/load_balancer/60ecb2bf67774900350d9c42", nil)
 	c.NoError(err)
 
 	rr := httptest.NewRecorder()
@@ -1127,51 +1702,96 @@ func TestRouter_CreateRedirect(t *testing.T) {
 	router, err := newTestRouter()
 	c.NoError(err)
 
-	redirectToReturn := &repository.Redirect{ID: "60ddc61ew3h4rn4nfnkkdf93", BlockchainID: "0021"}
+	router.Router.ServeHTTP(rr, req)
 
-	writerMock := &writerMock{}
+	c.Equal(http.StatusOK, rr.Code)
 
-	writerMock.On("WriteRedirect", mock.Anything).Return(redirectToReturn, nil).Once()
+	var marshaledBody types.LoadBalancer
 
-	router.Writer = writerMock
+	err = json.Unmarshal(rr.Body.Bytes(), &marshaledBody)
+	c.NoError(err)
+
+	c.Equal("60ecb2bf67774900350d9c42", marshaledBody.ID)
+	c.Nil(marshaledBody.RemovedAt)
+
+	req, err = http.NewRequest(http.MethodGet, "/load_balancer/60ecb2bf67774900350d9c43", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
 
 	router.Router.ServeHTTP(rr, req)
 
 	c.Equal(http.StatusOK, rr.Code)
 
-	marshaledReturnRedirect, err := json.Marshal(redirectToReturn)
+	marshaledBody = types.LoadBalancer{}
+
+	err = json.Unmarshal(rr.Body.Bytes(), &marshaledBody)
 	c.NoError(err)
 
-	c.Equal(marshaledReturnRedirect, rr.Body.Bytes())
+	c.Equal("60ecb2bf67774900350d9c43", marshaledBody.ID)
+	c.Equal(time.Date(2022, time.July, 21, 0, 0, 0, 0, time.UTC), *marshaledBody.RemovedAt)
 
-	req, err = http.NewRequest(http.MethodPost, "/redirect", bytes.NewBuffer([]byte("wrong")))
+	req, err = http.NewRequest(http.MethodGet, "/load_balancer/60fcb2bf67774900350d9c42", nil)
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
 
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusBadRequest, rr.Code)
+	c.Equal(http.StatusNotFound, rr.Code)
+}
 
-	req, err = http.NewRequest(http.MethodPost, "/redirect", bytes.NewBuffer(redirectToSend))
+func TestRouter_GetLoadBalancersByIDs(t *testing.T) {
+	c := require.New(t)
+
+	rawReq, err := json.Marshal(types.IDs{
+		IDs: []string{"60ecb2bf67774900350d9c42", "60fcb2bf67774900350d9c42", "60ecb2bf67774900350d9c43"},
+	})
 	c.NoError(err)
 
-	rr = httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPost, "/load_balancer/batch", bytes.NewBuffer(rawReq))
+	c.NoError(err)
 
-	writerMock.On("WriteRedirect", mock.Anything).Return(redirectToReturn, errors.New("dummy error")).Once()
+	rr := httptest.NewRecorder()
+
+	router, err := newTestRouter()
+	c.NoError(err)
 
 	router.Router.ServeHTTP(rr, req)
 
-	c.Equal(http.StatusInternalServerError, rr.Code)
+	c.Equal(http.StatusOK, rr.Code)
+
+	var marshaledBody types.BatchLoadBalancers
+
+	err = json.Unmarshal(rr.Body.Bytes(), &marshaledBody)
+	c.NoError(err)
+
+	c.Len(marshaledBody.LoadBalancers, 2)
+	c.Equal("60ecb2bf67774900350d9c42", marshaledBody.LoadBalancers[0].ID)
+	c.Equal("60ecb2bf67774900350d9c43", marshaledBody.LoadBalancers[1].ID)
+	c.Equal([]string{"60fcb2bf67774900350d9c42"}, marshaledBody.Missing)
+
+	req, err = http.NewRequest(http.MethodPost, "/load_balancer/batch", bytes.NewBufferString(`{"ids":[]}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
 }
 
-func TestRouter_ActivateBlockchain(t *testing.T) {
+func TestRouter_CreateLoadBalancer(t *testing.T) {
 	c := require.New(t)
 
-	activeStatusToSend, err := json.Marshal(true)
+	rawLBToSend := &repository.LoadBalancer{
+		UserID: "60ddc61b6e29c3003378361D",
+	}
+
+	lbToSend, err := json.Marshal(rawLBToSend)
 	c.NoError(err)
 
-	req, err := http.NewRequest(http.MethodPost, "/blockchain/0021/activate", bytes.NewBuffer(activeStatusToSend))
+	req, err := http.NewRequest(http.MethodPost, "/load_balancer", bytes.NewBuffer(lbToSend))
 	c.NoError(err)
 
 	rr := httptest.NewRecorder()
@@ -1179,9 +1799,14 @@ func TestRouter_ActivateBlockchain(t *testing.T) {
 	router, err := newTestRouter()
 	c.NoError(err)
 
+	lbToReturn := &repository.LoadBalancer{
+		ID:     "60ddc61b6e29c3003378361E",
+		UserID: "60ddc61b6e29c3003378361D",
+	}
+
 	writerMock := &writerMock{}
 
-	writerMock.On("ActivateBlockchain", mock.Anything).Return(nil).Once()
+	writerMock.On("WriteLoadBalancer", mock.Anything).Return(lbToReturn, nil).Once()
 
 	router.Writer = writerMock
 
@@ -1189,7 +1814,12 @@ func TestRouter_ActivateBlockchain(t *testing.T) {
 
 	c.Equal(http.StatusOK, rr.Code)
 
-	req, err = http.NewRequest(http.MethodPost, "/blockchain/0021/activate", bytes.NewBuffer([]byte("wrong")))
+	marshaledReturnLB, err := json.Marshal(lbToReturn)
+	c.NoError(err)
+
+	c.Equal(marshaledReturnLB, rr.Body.Bytes())
+
+	req, err = http.NewRequest(http.MethodPost, "/load_balancer", bytes.NewBuffer([]byte("wrong")))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
@@ -1198,14 +1828,2251 @@ func TestRouter_ActivateBlockchain(t *testing.T) {
 
 	c.Equal(http.StatusBadRequest, rr.Code)
 
-	req, err = http.NewRequest(http.MethodPost, "/blockchain/0021/activate", bytes.NewBuffer(activeStatusToSend))
+	req, err = http.NewRequest(http.MethodPost, "/load_balancer", bytes.NewBuffer(lbToSend))
 	c.NoError(err)
 
 	rr = httptest.NewRecorder()
 
-	writerMock.On("ActivateBlockchain", mock.Anything).Return(errors.New("dummy error")).Once()
+	writerMock.On("WriteLoadBalancer", mock.Anything).Return(lbToReturn, errors.New("dummy error")).Once()
 
 	router.Router.ServeHTTP(rr, req)
 
 	c.Equal(http.StatusInternalServerError, rr.Code)
 }
+
+func TestRouter_UpdateLoadBalancer(t *testing.T) {
+	c := require.New(t)
+
+	rawUpdateInput := &repository.UpdateLoadBalancer{
+		Name: "pablo",
+		StickyOptions: &repository.StickyOptions{
+			Duration:      "21",
+			StickyOrigins: []string{"pjog"},
+			StickyMax:     21,
+			Stickiness:    true,
+		},
+	}
+
+	updateInputToSend, err := json.Marshal(rawUpdateInput)
+	c.NoError(err)
+
+	req, err := http.NewRequest(http.MethodPut, "/load_balancer/60ecb2bf67774900350d9c42", bytes.NewBuffer(updateInputToSend))
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	writerMock.On("UpdateLoadBalancer", mock.Anything).Return(time.Time{}, nil).Once()
+
+	router.Writer = writerMock
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPut, "/load_balancer/5f62b7d8be3591c4dea85664", bytes.NewBuffer(updateInputToSend))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotFound, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPut, "/load_balancer/60ecb2bf67774900350d9c42", bytes.NewBuffer([]byte("wrong")))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPut, "/load_balancer/60ecb2bf67774900350d9c42", bytes.NewBuffer(updateInputToSend))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	writerMock.On("UpdateLoadBalancer", mock.Anything).Return(time.Time{}, errors.New("dummy error")).Once()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusInternalServerError, rr.Code)
+}
+
+func TestRouter_UpdateIfMatch(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	router.Writer = writerMock
+
+	updatedAt := time.Date(2022, time.November, 1, 10, 30, 0, 123456000, time.UTC)
+	ifMatch := `"` + updatedAt.Format(time.RFC3339Nano) + `"`
+
+	put := func(path, body, ifMatch string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPut, path, bytes.NewBufferString(body))
+		c.NoError(err)
+
+		req.Header.Set("If-Match", ifMatch)
+
+		rr := httptest.NewRecorder()
+
+		router.Router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	newUpdatedAt := time.Date(2022, time.November, 2, 10, 30, 0, 0, time.UTC)
+
+	// the precondition is checked by the same write that saves the update
+	writerMock.On("UpdateApplication", &updatedAt).Return(newUpdatedAt, nil).Once()
+
+	rr := put("/application/5f62b7d8be3591c4dea8566d", `{"name":"pablo"}`, ifMatch)
+	c.Equal(http.StatusOK, rr.Code)
+
+	var updatedApp repository.Application
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &updatedApp))
+	c.Equal(newUpdatedAt, updatedApp.UpdatedAt)
+	c.Equal(newUpdatedAt, router.Cache.GetApplication("5f62b7d8be3591c4dea8566d").UpdatedAt)
+
+	writerMock.On("UpdateApplication", &updatedAt).Return(time.Time{}, types.ErrApplicationChanged).Once()
+
+	c.Equal(http.StatusPreconditionFailed, put("/application/5f62b7d8be3591c4dea8566d", `{"name":"pablo"}`, ifMatch).Code)
+
+	writerMock.On("RemoveApplication", &updatedAt).Return(time.Time{}, types.ErrApplicationChanged).Once()
+
+	c.Equal(http.StatusPreconditionFailed, put("/application/5f62b7d8be3591c4dea8566d", `{"remove":true}`, "W/"+ifMatch).Code)
+
+	writerMock.On("UpdateApplication", (*time.Time)(nil)).Return(newUpdatedAt, nil).Once()
+
+	c.Equal(http.StatusOK, put("/application/5f62b7d8be3591c4dea8566d", `{"name":"pablo"}`, "*").Code)
+
+	c.Equal(http.StatusBadRequest, put("/application/5f62b7d8be3591c4dea8566d", `{"name":"pablo"}`, `"yesterday"`).Code)
+
+	// a write failing for another reason is not a failed precondition
+	writerMock.On("UpdateApplication", &updatedAt).Return(time.Time{}, errors.New("dummy error")).Once()
+
+	c.Equal(http.StatusUnprocessableEntity, put("/application/5f62b7d8be3591c4dea8566d", `{"name":"pablo"}`, ifMatch).Code)
+
+	writerMock.On("UpdateLoadBalancer", &updatedAt).Return(newUpdatedAt, nil).Once()
+
+	rr = put("/load_balancer/60ecb2bf67774900350d9c42", `{"name":"pablo"}`, ifMatch)
+	c.Equal(http.StatusOK, rr.Code)
+
+	var updatedLB repository.LoadBalancer
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &updatedLB))
+	c.Equal(newUpdatedAt, updatedLB.UpdatedAt)
+
+	writerMock.On("RemoveLoadBalancer", &updatedAt).Return(time.Time{}, types.ErrLoadBalancerChanged).Once()
+
+	c.Equal(http.StatusPreconditionFailed, put("/load_balancer/60ecb2bf67774900350d9c42", `{"remove":true}`, ifMatch).Code)
+
+	// removed load balancers are refused before being written
+	c.Equal(http.StatusUnprocessableEntity, put("/load_balancer/60ecb2bf67774900350d9c43", `{"name":"pablo"}`, ifMatch).Code)
+
+	writerMock.AssertExpectations(t)
+}
+
+func TestRouter_PatchApplication(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	router.Writer = writerMock
+
+	patch := func(path, body, contentType string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPatch, path, bytes.NewBufferString(body))
+		c.NoError(err)
+
+		req.Header.Set("Content-Type", contentType)
+
+		rr := httptest.NewRecorder()
+
+		router.Router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	var written *repository.Application
+
+	updatedAt := time.Date(2022, time.November, 2, 10, 30, 0, 0, time.UTC)
+
+	writerMock.On("PatchApplication", mock.MatchedBy(func(app *repository.Application) bool {
+		written = app
+		return true
+	}), (*time.Time)(nil)).Return(updatedAt, nil).Once()
+
+	rr := patch("/application/5f62b7d8be3591c4dea8566d", `{"name":"pablo","firstDateSurpassed":null,
+		"limit":{"payPlan":{"planType":"PAY_AS_YOU_GO_V0"}},"gatewaySettings":{"whitelistOrigins":["origin-1"]}}`,
+		mergePatchContentType)
+	c.Equal(http.StatusOK, rr.Code)
+
+	c.Equal("5f62b7d8be3591c4dea8566d", written.ID)
+	c.Equal("60ecb2bf67774900350d9c43", written.UserID)
+	c.Equal("pablo", written.Name)
+	c.True(written.FirstDateSurpassed.IsZero())
+	c.Equal(repository.PayAsYouGoV0, written.Limit.PayPlan.Type)
+	c.Equal([]string{"origin-1"}, written.GatewaySettings.WhitelistOrigins)
+
+	var patchedApp repository.Application
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &patchedApp))
+	c.Equal("pablo", patchedApp.Name)
+	c.Equal(0, patchedApp.Limit.PayPlan.Limit)
+	c.Equal(updatedAt, patchedApp.UpdatedAt)
+
+	// responses are copies, the cache is only updated by the database notification
+	c.Empty(router.Cache.GetApplication("5f62b7d8be3591c4dea8566d").Name)
+
+	c.Equal(http.StatusNotFound, patch("/application/5f62b7d8be3591c4dea85664", `{"name":"pablo"}`, mergePatchContentType).Code)
+	c.Equal(http.StatusUnsupportedMediaType, patch("/application/5f62b7d8be3591c4dea8566d", `{"name":"pablo"}`, "text/plain").Code)
+	c.Equal(http.StatusBadRequest, patch("/application/5f62b7d8be3591c4dea8566d", `["pablo"]`, mergePatchContentType).Code)
+	c.Equal(http.StatusBadRequest, patch("/application/5f62b7d8be3591c4dea8566d", `{"name":1}`, mergePatchContentType).Code)
+	c.Equal(http.StatusUnprocessableEntity, patch("/application/5f62b7d8be3591c4dea8566d", `{"userID":"pablo"}`, mergePatchContentType).Code)
+	c.Equal(http.StatusUnprocessableEntity, patch("/application/5f62b7d8be3591c4dea8566d", `{"status":"WRONG"}`, mergePatchContentType).Code)
+	c.Equal(http.StatusUnprocessableEntity, patch("/application/5f62b7d8be3591c4dea8566d",
+		`{"gatewaySettings":{"whitelistMethods":[{"blockchainID":"0021","methods":["eth call"]}]}}`, mergePatchContentType).Code)
+
+	writerMock.On("PatchApplication", mock.Anything, mock.Anything).Return(time.Time{}, errors.New("dummy error")).Once()
+
+	c.Equal(http.StatusUnprocessableEntity, patch("/application/5f62b7d8be3591c4dea8566d", `{"name":"pablo"}`, "application/json").Code)
+
+	writerMock.AssertExpectations(t)
+}
+
+func TestRouter_PatchLoadBalancer(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	router.Writer = writerMock
+
+	patch := func(path, body string) int {
+		req, err := http.NewRequest(http.MethodPatch, path, bytes.NewBufferString(body))
+		c.NoError(err)
+
+		rr := httptest.NewRecorder()
+
+		router.Router.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	var written *repository.LoadBalancer
+
+	writerMock.On("PatchLoadBalancer", mock.MatchedBy(func(lb *repository.LoadBalancer) bool {
+		written = lb
+		return true
+	}), (*time.Time)(nil)).Return(time.Now(), nil).Once()
+
+	c.Equal(http.StatusOK, patch("/load_balancer/60ecb2bf67774900350d9c42", `{"name":"pablo","stickinessOptions":{"stickyOrigins":null,"stickyMax":2}}`))
+
+	c.Equal("60ecb2bf67774900350d9c42", written.ID)
+	c.Equal("pablo", written.Name)
+	c.Equal(2, written.StickyOptions.StickyMax)
+	c.Empty(written.StickyOptions.StickyOrigins)
+	c.Equal("60ecb2bf67774900350d9c43", written.UserID)
+
+	c.Equal(http.StatusNotFound, patch("/load_balancer/60ecb2bf67774900350d9c41", `{"name":"pablo"}`))
+	c.Equal(http.StatusUnprocessableEntity, patch("/load_balancer/60ecb2bf67774900350d9c43", `{"name":"pablo"}`))
+	c.Equal(http.StatusUnprocessableEntity, patch("/load_balancer/60ecb2bf67774900350d9c42", `{"applicationIDs":null}`))
+
+	writerMock.On("PatchLoadBalancer", mock.Anything, mock.Anything).Return(time.Time{}, errors.New("dummy error")).Once()
+
+	c.Equal(http.StatusInternalServerError, patch("/load_balancer/60ecb2bf67774900350d9c42", `{"name":"pablo"}`))
+
+	writerMock.AssertExpectations(t)
+}
+
+func TestMergePatch(t *testing.T) {
+	c := require.New(t)
+
+	// examples from RFC 7386 appendix A
+	examples := [][3]string{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, example := range examples {
+		target, err := decodeJSON([]byte(example[0]))
+		c.NoError(err)
+
+		patch, err := decodeJSON([]byte(example[1]))
+		c.NoError(err)
+
+		patched, err := json.Marshal(mergePatch(target, patch))
+		c.NoError(err)
+
+		c.JSONEq(example[2], string(patched), example[1])
+	}
+}
+
+func TestRouter_UpdateApplicationWhitelist(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	router.Writer = writerMock
+
+	updatedAt := time.Date(2022, time.July, 21, 0, 0, 0, 0, time.UTC)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, path, bytes.NewBufferString(body))
+		c.NoError(err)
+
+		rr := httptest.NewRecorder()
+
+		router.Router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	writerMock.On("UpdateWhitelist", "5f62b7d8be3591c4dea8566d", types.WhitelistChange{
+		Type:   types.WhitelistOrigins,
+		Values: []string{"https://pokt.network"},
+	}).Return(&repository.GatewaySettings{WhitelistOrigins: []string{"https://pokt.network"}}, updatedAt, nil).Once()
+
+	rr := send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/whitelist/origins/https%3A%2F%2Fpokt.network", "")
+	c.Equal(http.StatusOK, rr.Code)
+
+	var app repository.Application
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &app))
+	c.Equal("5f62b7d8be3591c4dea8566d", app.ID)
+	c.Equal([]string{"https://pokt.network"}, app.GatewaySettings.WhitelistOrigins)
+	c.True(updatedAt.Equal(app.UpdatedAt))
+
+	// responses are copies, the cache is only updated by the database notification
+	c.Empty(router.Cache.GetApplication("5f62b7d8be3591c4dea8566d").GatewaySettings.WhitelistOrigins)
+
+	writerMock.On("UpdateWhitelist", "5f62b7d8be3591c4dea8566d", types.WhitelistChange{
+		Type:   types.WhitelistOrigins,
+		Values: []string{"https://pokt.network"},
+		Remove: true,
+	}).Return(&repository.GatewaySettings{}, time.Time{}, nil).Once()
+
+	rr = send(http.MethodDelete, "/application/5f62b7d8be3591c4dea8566d/whitelist/origins/https%3A%2F%2Fpokt.network", "")
+	c.Equal(http.StatusOK, rr.Code)
+
+	writerMock.On("UpdateWhitelist", "5f62b7d8be3591c4dea8566d", types.WhitelistChange{
+		Type:   types.WhitelistBlockchains,
+		Values: []string{"0021"},
+	}).Return(&repository.GatewaySettings{WhitelistBlockchains: []string{"0021"}}, time.Time{}, nil).Once()
+
+	c.Equal(http.StatusOK, send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/whitelist/blockchains/0021", "").Code)
+	c.Equal(http.StatusUnprocessableEntity, send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/whitelist/blockchains/9999", "").Code)
+
+	// blockchains no longer existing can still be removed
+	writerMock.On("UpdateWhitelist", "5f62b7d8be3591c4dea8566d", types.WhitelistChange{
+		Type:   types.WhitelistBlockchains,
+		Values: []string{"9999"},
+		Remove: true,
+	}).Return(&repository.GatewaySettings{}, time.Time{}, nil).Once()
+
+	c.Equal(http.StatusOK, send(http.MethodDelete, "/application/5f62b7d8be3591c4dea8566d/whitelist/blockchains/9999", "").Code)
+
+	writerMock.On("UpdateWhitelist", "5f62b7d8be3591c4dea8566d", types.WhitelistChange{
+		Type:         types.WhitelistContracts,
+		BlockchainID: "0021",
+		Values:       []string{"0xabc"},
+	}).Return(&repository.GatewaySettings{WhitelistContracts: []repository.WhitelistContract{
+		{BlockchainID: "0021", Contracts: []string{"0xabc"}},
+	}}, time.Time{}, nil).Once()
+
+	rr = send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/whitelist/contracts", `{"blockchainID":"0021","contracts":["0xabc"]}`)
+	c.Equal(http.StatusOK, rr.Code)
+
+	app = repository.Application{}
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &app))
+	c.Len(app.GatewaySettings.WhitelistContracts, 1)
+
+	writerMock.On("UpdateWhitelist", "5f62b7d8be3591c4dea8566d", types.WhitelistChange{
+		Type:         types.WhitelistMethods,
+		BlockchainID: "0022",
+		Remove:       true,
+	}).Return(&repository.GatewaySettings{}, time.Time{}, nil).Once()
+
+	c.Equal(http.StatusOK, send(http.MethodDelete, "/application/5f62b7d8be3591c4dea8566d/whitelist/methods", `{"blockchainID":"0022"}`).Code)
+
+	c.Equal(http.StatusUnprocessableEntity, send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/whitelist/methods", `{"blockchainID":"9999","methods":["eth_call"]}`).Code)
+	c.Equal(http.StatusBadRequest, send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/whitelist/contracts", `{"blockchainID":"0021"}`).Code)
+	c.Equal(http.StatusBadRequest, send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/whitelist/contracts",
+		`{"blockchainID":"0021","contracts":["0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"]}`).Code)
+	c.Equal(http.StatusBadRequest, send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/whitelist/methods",
+		`{"blockchainID":"0021","methods":["eth call"]}`).Code)
+	c.Equal(http.StatusBadRequest, send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/whitelist/contracts", "wrong").Code)
+	c.Equal(http.StatusNotFound, send(http.MethodPost, "/application/5f62b7d8be3591c4dea85664/whitelist/origins/pokt.network", "").Code)
+
+	writerMock.On("UpdateWhitelist", "5f62b7d8be3591c4dea8566d", mock.Anything).Return((*repository.GatewaySettings)(nil), time.Time{}, errors.New("dummy error")).Once()
+
+	c.Equal(http.StatusInternalServerError, send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/whitelist/user_agents/curl", "").Code)
+
+	writerMock.On("UpdateWhitelist", "5f62b7d8be3591c4dea8566d", mock.Anything).Return((*repository.GatewaySettings)(nil), time.Time{}, types.ErrApplicationNotFound).Once()
+
+	c.Equal(http.StatusNotFound, send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/whitelist/user_agents/curl", "").Code)
+
+	writerMock.On("UpdateWhitelist", "5f62b7d8be3591c4dea8566d", mock.Anything).Return((*repository.GatewaySettings)(nil), time.Time{},
+		fmt.Errorf("%w: %q", types.ErrInvalidContract, "0x")).Once()
+
+	c.Equal(http.StatusUnprocessableEntity, send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/whitelist/user_agents/curl", "").Code)
+
+	writerMock.AssertExpectations(t)
+}
+
+func TestRouter_RemoveLoadBalancer(t *testing.T) {
+	c := require.New(t)
+
+	rawUpdateInput := &repository.UpdateLoadBalancer{
+		Remove: true,
+	}
+
+	updateInputToSend, err := json.Marshal(rawUpdateInput)
+	c.NoError(err)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	router.Writer = writerMock
+
+	req, err := http.NewRequest(http.MethodPut, "/load_balancer/60ecb2bf67774900350d9c42", bytes.NewBuffer(updateInputToSend))
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	writerMock.On("RemoveLoadBalancer", mock.Anything).Return(time.Time{}, errors.New("dummy error")).Once()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusInternalServerError, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPut, "/load_balancer/60ecb2bf67774900350d9c42", bytes.NewBuffer(updateInputToSend))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	writerMock.On("RemoveLoadBalancer", mock.Anything).Return(time.Time{}, nil).Once()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPut, "/load_balancer/5f62b7d8be3591c4dea85664", bytes.NewBuffer(updateInputToSend))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotFound, rr.Code)
+}
+
+func TestRouter_GetPayPlans(t *testing.T) {
+	c := require.New(t)
+
+	req, err := http.NewRequest(http.MethodGet, "/pay_plan", nil)
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	expectedBody, err := json.Marshal([]*repository.PayPlan{
+		{
+			Type:  repository.FreetierV0,
+			Limit: 250000,
+		},
+		{
+			Type:  repository.PayAsYouGoV0,
+			Limit: 0,
+		},
+	})
+	c.NoError(err)
+
+	c.Equal(expectedBody, rr.Body.Bytes())
+}
+
+func TestRouter_GetPayPlan(t *testing.T) {
+	c := require.New(t)
+
+	req, err := http.NewRequest(http.MethodGet, "/pay_plan/freetier_v0", nil)
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	expectedBody, err := json.Marshal(&repository.PayPlan{
+		Type:  repository.FreetierV0,
+		Limit: 250000,
+	})
+	c.NoError(err)
+
+	c.Equal(expectedBody, rr.Body.Bytes())
+
+	req, err = http.NewRequest(http.MethodGet, "/pay_plan/freetier_v21", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotFound, rr.Code)
+}
+
+func TestRouter_CreateBlockchain(t *testing.T) {
+	c := require.New(t)
+
+	rawChainToSend := &repository.Blockchain{
+		Ticker: "POKT",
+	}
+
+	chainToSend, err := json.Marshal(rawChainToSend)
+	c.NoError(err)
+
+	req, err := http.NewRequest(http.MethodPost, "/blockchain", bytes.NewBuffer(chainToSend))
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	chainToReturn := &repository.Blockchain{
+		ID:     "60ddc61b6e29c3003378361E",
+		Ticker: "POKT",
+	}
+
+	writerMock := &writerMock{}
+
+	writerMock.On("WriteBlockchain", mock.Anything).Return(chainToReturn, nil).Once()
+
+	router.Writer = writerMock
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	marshaledReturnChain, err := json.Marshal(chainToReturn)
+	c.NoError(err)
+
+	c.Equal(marshaledReturnChain, rr.Body.Bytes())
+
+	req, err = http.NewRequest(http.MethodPost, "/blockchain", bytes.NewBuffer([]byte("wrong")))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/blockchain", bytes.NewBuffer(chainToSend))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	writerMock.On("WriteBlockchain", mock.Anything).Return(chainToReturn, errors.New("dummy error")).Once()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusInternalServerError, rr.Code)
+}
+
+func TestRouter_CreateRedirect(t *testing.T) {
+	c := require.New(t)
+
+	rawRedirectsToSend := &repository.Redirect{BlockchainID: "0021"}
+
+	redirectToSend, err := json.Marshal(rawRedirectsToSend)
+	c.NoError(err)
+
+	req, err := http.NewRequest(http.MethodPost, "/redirect", bytes.NewBuffer(redirectToSend))
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	redirectToReturn := &repository.Redirect{ID: "60ddc61ew3h4rn4nfnkkdf93", BlockchainID: "0021"}
+
+	writerMock := &writerMock{}
+
+	writerMock.On("WriteRedirect", mock.Anything).Return(redirectToReturn, nil).Once()
+
+	router.Writer = writerMock
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	marshaledReturnRedirect, err := json.Marshal(redirectToReturn)
+	c.NoError(err)
+
+	c.Equal(marshaledReturnRedirect, rr.Body.Bytes())
+
+	req, err = http.NewRequest(http.MethodPost, "/redirect", bytes.NewBuffer([]byte("wrong")))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/redirect", bytes.NewBuffer(redirectToSend))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	writerMock.On("WriteRedirect", mock.Anything).Return(redirectToReturn, errors.New("dummy error")).Once()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusInternalServerError, rr.Code)
+}
+
+func TestRouter_ActivateBlockchain(t *testing.T) {
+	c := require.New(t)
+
+	activeStatusToSend, err := json.Marshal(true)
+	c.NoError(err)
+
+	req, err := http.NewRequest(http.MethodPost, "/blockchain/0021/activate", bytes.NewBuffer(activeStatusToSend))
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	writerMock.On("ActivateBlockchain", mock.Anything).Return(nil).Once()
+
+	router.Writer = writerMock
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/blockchain/0021/activate", bytes.NewBuffer([]byte("wrong")))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/blockchain/0021/activate", bytes.NewBuffer(activeStatusToSend))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	writerMock.On("ActivateBlockchain", mock.Anything).Return(errors.New("dummy error")).Once()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusInternalServerError, rr.Code)
+}
+
+func TestRouter_GetRedirectByDomain(t *testing.T) {
+	c := require.New(t)
+
+	req, err := http.NewRequest(http.MethodGet, "/redirect/domain/eth-mainnet.gateway.network", nil)
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	expectedBody, err := json.Marshal(&repository.Redirect{
+		BlockchainID:   "0022",
+		Alias:          "eth-mainnet",
+		Domain:         "eth-mainnet.gateway.network",
+		LoadBalancerID: "45678",
+	})
+	c.NoError(err)
+
+	c.Equal(expectedBody, rr.Body.Bytes())
+
+	// path variables reach the handlers unescaped
+	req, err = http.NewRequest(http.MethodGet, "/redirect/domain/eth%2Dmainnet.gateway.network", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.Equal(expectedBody, rr.Body.Bytes())
+
+	req, err = http.NewRequest(http.MethodGet, "/redirect/domain/not-real.gateway.network", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotFound, rr.Code)
+}
+
+func TestRouter_UpdateBlockchain(t *testing.T) {
+	c := require.New(t)
+
+	rawUpdateInput := &types.UpdateBlockchain{
+		Altruist:          "https://altruist.com",
+		RequestTimeout:    5000,
+		BlockchainAliases: []string{"pokt-mainnet", "pokt"},
+		SyncCheckOptions: &repository.SyncCheckOptions{
+			Body: "body",
+		},
+	}
+
+	updateInputToSend, err := json.Marshal(rawUpdateInput)
+	c.NoError(err)
+
+	req, err := http.NewRequest(http.MethodPut, "/blockchain/0021", bytes.NewBuffer(updateInputToSend))
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router, err := newTestRouterWithBlockchains([]*repository.Blockchain{
+		{
+			ID:                "0021",
+			BlockchainAliases: []string{"pokt-mainnet"},
+		},
+		{
+			ID: "0022",
+		},
+	})
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	writerMock.On("UpdateBlockchain", mock.Anything).Return(nil).Once()
+
+	router.Writer = writerMock
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var blockchain repository.Blockchain
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &blockchain))
+	c.Equal("https://altruist.com", blockchain.Altruist)
+	c.Equal(5000, blockchain.RequestTimeout)
+	c.Equal([]string{"pokt-mainnet", "pokt"}, blockchain.BlockchainAliases)
+	c.Equal("0021", blockchain.SyncCheckOptions.BlockchainID)
+	c.Equal("body", blockchain.SyncCheckOptions.Body)
+
+	// responses are copies, the cache is only updated by the database notification
+	c.Empty(router.Cache.GetBlockchain("0021").Altruist)
+
+	req, err = http.NewRequest(http.MethodPut, "/blockchain/0021?fields=altruist", bytes.NewBuffer(updateInputToSend))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	writerMock.On("UpdateBlockchain", mock.Anything).Return(nil).Once()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.JSONEq(`{"altruist":"https://altruist.com"}`, rr.Body.String())
+
+	req, err = http.NewRequest(http.MethodPut, "/blockchain/0021", bytes.NewBuffer([]byte("wrong")))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPut, "/blockchain/0022", bytes.NewBuffer(updateInputToSend))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusConflict, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPut, "/blockchain/0023", bytes.NewBuffer(updateInputToSend))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotFound, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPut, "/blockchain/0021", bytes.NewBuffer(updateInputToSend))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	writerMock.On("UpdateBlockchain", mock.Anything).Return(errors.New("dummy error")).Once()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPut, "/blockchain/0021", bytes.NewBufferString(`{"blockchainAliases":["pokt","POKT"]}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	// aliases are rejected on creation too
+	req, err = http.NewRequest(http.MethodPost, "/blockchain", bytes.NewBufferString(`{"blockchainAliases":["POKT-mainnet"]}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusConflict, rr.Code)
+}
+
+func TestRouter_GetChanges(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	req, err := http.NewRequest(http.MethodGet, "/changes", nil)
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var changes types.Changes
+
+	err = json.Unmarshal(rr.Body.Bytes(), &changes)
+	c.NoError(err)
+
+	c.True(changes.Resync)
+	c.Len(changes.Applications, 3)
+	c.Len(changes.LoadBalancers, 2)
+	c.Len(changes.Blockchains, 2)
+	c.Empty(changes.Removed)
+
+	req, err = http.NewRequest(http.MethodGet, "/changes?since="+changes.Version, nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var newChanges types.Changes
+
+	err = json.Unmarshal(rr.Body.Bytes(), &newChanges)
+	c.NoError(err)
+
+	c.False(newChanges.Resync)
+	c.Equal(changes.Version, newChanges.Version)
+	c.Empty(newChanges.Applications)
+	c.Empty(newChanges.LoadBalancers)
+	c.Empty(newChanges.Blockchains)
+
+	// Versions from another instance ask for a resync
+	req, err = http.NewRequest(http.MethodGet, "/changes?since=0a1b2c3d-1", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	err = json.Unmarshal(rr.Body.Bytes(), &newChanges)
+	c.NoError(err)
+
+	c.True(newChanges.Resync)
+	c.Len(newChanges.Applications, 3)
+
+	req, err = http.NewRequest(http.MethodGet, "/changes?since=wrong", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func TestRouter_CompressionHandler(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	req, err := http.NewRequest(http.MethodGet, "/application", nil)
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.Empty(rr.Header().Get("Content-Encoding"))
+
+	expectedBody := rr.Body.Bytes()
+	etag := rr.Header().Get("ETag")
+
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.Equal("gzip", rr.Header().Get("Content-Encoding"))
+	c.Equal("Accept-Encoding", rr.Header().Get("Vary"))
+
+	gzipReader, err := gzip.NewReader(rr.Body)
+	c.NoError(err)
+
+	body, err := io.ReadAll(gzipReader)
+	c.NoError(err)
+	c.Equal(expectedBody, body)
+
+	req.Header.Set("Accept-Encoding", "gzip;q=0.8, br")
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.Equal("br", rr.Header().Get("Content-Encoding"))
+	c.NotEqual(etag, rr.Header().Get("ETag"))
+
+	body, err = io.ReadAll(brotli.NewReader(rr.Body))
+	c.NoError(err)
+	c.Equal(expectedBody, body)
+
+	// Responses without body are left untouched
+	req.Header.Set("If-None-Match", rr.Header().Get("ETag"))
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotModified, rr.Code)
+	c.Empty(rr.Header().Get("Content-Encoding"))
+	c.Empty(rr.Body.Bytes())
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal("", negotiateEncoding(""))
+	c.Equal("", negotiateEncoding("identity, deflate"))
+	c.Equal("gzip", negotiateEncoding("gzip"))
+	c.Equal("br", negotiateEncoding("gzip, br"))
+	c.Equal("gzip", negotiateEncoding("gzip;q=1.0, br;q=0.5"))
+	c.Equal("gzip", negotiateEncoding("br;q=0, gzip"))
+	c.Equal("", negotiateEncoding("br;q=0, gzip;q=0"))
+}
+
+func TestRespondWithJSONList(t *testing.T) {
+	c := require.New(t)
+
+	lists := []any{
+		[]*repository.Application(nil),
+		[]*repository.Application{},
+		[]*repository.Application{{ID: "5f62b7d8be3591c4dea8566d", Name: "<pablo>"}, nil, {ID: "5f62b7d8be3591c4dea8566a"}},
+		[]repository.AppLimits{{AppID: "5f62b7d8be3591c4dea8566d"}},
+	}
+
+	for _, list := range lists {
+		expectedBody, err := json.Marshal(list)
+		c.NoError(err)
+
+		rr := httptest.NewRecorder()
+
+		switch items := list.(type) {
+		case []*repository.Application:
+			err = respondWithJSONList(rr, http.StatusOK, items, encoder{})
+		case []repository.AppLimits:
+			err = respondWithJSONList(rr, http.StatusOK, items, encoder{})
+		}
+		c.NoError(err)
+
+		c.Equal(http.StatusOK, rr.Code)
+		c.Equal("application/json", rr.Header().Get("Content-Type"))
+		c.Equal(expectedBody, rr.Body.Bytes())
+	}
+}
+
+func TestRespondWithJSONList_EncodeFailure(t *testing.T) {
+	c := require.New(t)
+
+	rr := httptest.NewRecorder()
+
+	err := respondWithJSONList(rr, http.StatusOK, []any{"first", make(chan int)}, encoder{})
+	c.Error(err)
+
+	c.Equal(http.StatusInternalServerError, rr.Code)
+	c.Contains(rr.Body.String(), "unsupported type")
+
+	rr = httptest.NewRecorder()
+
+	err = respondWithJSONList(rr, http.StatusOK, []any{strings.Repeat("a", streamBufferSize), make(chan int)}, encoder{})
+	c.Error(err)
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.False(json.Valid(rr.Body.Bytes()))
+}
+
+func TestRouter_Fields(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	req, err := http.NewRequest(http.MethodGet, "/application?fields=id,limit.payPlan.planType", nil)
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.Equal(`[{"id":"5f62b7d8be3591c4dea8566d","limit":{"payPlan":{"planType":"FREETIER_V0"}}},`+
+		`{"id":"5f62b7d8be3591c4dea8566a","limit":{"payPlan":{"planType":""}}},`+
+		`{"id":"5f62b7d8be3591c4dea8566f","limit":{"payPlan":{"planType":""}}}]`, rr.Body.String())
+
+	req, err = http.NewRequest(http.MethodGet, "/load_balancer/60ecb2bf67774900350d9c42?fields=id,Applications.id,unknown", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.Equal(`{"Applications":[{"id":"5f62b7d8be3591c4dea8566d"},{"id":"5f62b7d8be3591c4dea8566a"}],"id":"60ecb2bf67774900350d9c42"}`, rr.Body.String())
+
+	req, err = http.NewRequest(http.MethodGet, "/blockchain/0021?fields=id,redirects.alias", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.Equal(`{"id":"0021","redirects":[{"alias":"pokt-mainnet"}]}`, rr.Body.String())
+
+	// Wrappers keep their members and project the entities they hold
+	req, err = http.NewRequest(http.MethodPost, "/application/batch?fields=id",
+		bytes.NewBufferString(`{"ids":["5f62b7d8be3591c4dea8566d","5f62b7d8be3591c4dea85664"]}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.Equal(`{"applications":[{"id":"5f62b7d8be3591c4dea8566d"}],"missing":["5f62b7d8be3591c4dea85664"]}`, rr.Body.String())
+
+	req, err = http.NewRequest(http.MethodGet, "/user/60ecb2bf67774900350d9c43?fields=id", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var user struct {
+		Summary       json.RawMessage   `json:"summary"`
+		Applications  []json.RawMessage `json:"applications"`
+		LoadBalancers []json.RawMessage `json:"loadBalancers"`
+	}
+
+	err = json.Unmarshal(rr.Body.Bytes(), &user)
+	c.NoError(err)
+	c.NotEqual("{}", string(user.Summary))
+	c.JSONEq(`{"id":"5f62b7d8be3591c4dea8566d"}`, string(user.Applications[0]))
+	c.JSONEq(`{"id":"60ecb2bf67774900350d9c42"}`, string(user.LoadBalancers[0]))
+
+	// Payloads holding no entities reject fields
+	req, err = http.NewRequest(http.MethodPost, "/application/usage_state?fields=id",
+		bytes.NewBufferString(`{"updates":[{"applicationID":"5f62b7d8be3591c4dea85664","reset":true}]}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	req, err = http.NewRequest(http.MethodGet, "/blockchain?fields=id..path", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func TestParseFields(t *testing.T) {
+	c := require.New(t)
+
+	fields, err := parseFields("")
+	c.NoError(err)
+	c.Nil(fields)
+
+	fields, err = parseFields("id, limit.payPlan,limit.payPlan.type,,gatewaySettings")
+	c.NoError(err)
+	c.Equal(fieldSet{
+		"id":              nil,
+		"limit":           fieldSet{"payPlan": nil},
+		"gatewaySettings": nil,
+	}, fields)
+
+	_, err = parseFields("limit.")
+	c.ErrorIs(err, errInvalidFields)
+}
+
+func TestRouter_RedactSecrets(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	router.APIKeys = map[string]bool{"reader": true, "admin": true}
+	router.Capabilities = map[string]map[Capability]bool{"admin": {CapabilitySecretsRead: true}}
+
+	app := router.Cache.GetApplication("5f62b7d8be3591c4dea8566d")
+	app.GatewaySettings.SecretKey = "secret_key"
+	app.GatewayAAT.PrivateKey = "private_key"
+
+	paths := []string{
+		"/application",
+		"/application/5f62b7d8be3591c4dea8566d",
+		"/user/60ecb2bf67774900350d9c43/application",
+		"/load_balancer",
+		"/load_balancer/60ecb2bf67774900350d9c42",
+	}
+
+	for _, path := range paths {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		c.NoError(err)
+
+		req.Header.Set("Authorization", "reader")
+
+		rr := httptest.NewRecorder()
+
+		router.Router.ServeHTTP(rr, req)
+
+		c.Equal(http.StatusOK, rr.Code, path)
+		c.NotContains(rr.Body.String(), "secret_key", path)
+		c.NotContains(rr.Body.String(), "private_key", path)
+
+		req.Header.Set("Authorization", "admin")
+
+		rr = httptest.NewRecorder()
+
+		router.Router.ServeHTTP(rr, req)
+
+		c.Equal(http.StatusOK, rr.Code, path)
+		c.Contains(rr.Body.String(), "secret_key", path)
+		c.Contains(rr.Body.String(), "private_key", path)
+	}
+
+	// Cached applications are left untouched
+	c.Equal("secret_key", app.GatewaySettings.SecretKey)
+	c.Equal("private_key", app.GatewayAAT.PrivateKey)
+
+	rawReq, err := json.Marshal(types.IDs{IDs: []string{"5f62b7d8be3591c4dea8566d"}})
+	c.NoError(err)
+
+	req, err := http.NewRequest(http.MethodPost, "/application/batch", bytes.NewBuffer(rawReq))
+	c.NoError(err)
+
+	req.Header.Set("Authorization", "reader")
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.NotContains(rr.Body.String(), "secret_key")
+}
+
+func TestParseCapabilities(t *testing.T) {
+	c := require.New(t)
+
+	capabilities, err := ParseCapabilities("")
+	c.NoError(err)
+	c.Empty(capabilities)
+
+	capabilities, err = ParseCapabilities("admin=secrets:read|quotas:override, reader=secrets:read|secrets:read")
+	c.NoError(err)
+	c.Equal(map[string]map[Capability]bool{
+		"admin":  {CapabilitySecretsRead: true, CapabilityQuotasOverride: true},
+		"reader": {CapabilitySecretsRead: true},
+	}, capabilities)
+
+	_, err = ParseCapabilities("admin=secrets:write")
+	c.ErrorIs(err, errInvalidCapabilities)
+
+	_, err = ParseCapabilities("secrets:read")
+	c.ErrorIs(err, errInvalidCapabilities)
+}
+
+func TestRouter_RotateApplicationSecret(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	app := router.Cache.GetApplication("5f62b7d8be3591c4dea8566d")
+	app.GatewaySettings.SecretKey = "old_secret_key"
+	app.GatewaySettings.WhitelistOrigins = []string{"origin"}
+
+	writerMock := &writerMock{}
+
+	router.Writer = writerMock
+
+	// Rotated secrets are only handed to API keys able to read them
+	req, err := http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/rotate_secret", nil)
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusForbidden, rr.Code)
+
+	router.Capabilities = map[string]map[Capability]bool{"": {CapabilitySecretsRead: true}}
+
+	writerMock.On("UpdateSecretKey", mock.Anything).Return(nil).Once()
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var rotatedApp repository.Application
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &rotatedApp))
+	c.Len(rotatedApp.GatewaySettings.SecretKey, secretKeyLength)
+	c.NotEqual("old_secret_key", rotatedApp.GatewaySettings.SecretKey)
+	c.Equal([]string{"origin"}, rotatedApp.GatewaySettings.WhitelistOrigins)
+
+	// the cache is updated by the database notification
+	c.Equal("old_secret_key", app.GatewaySettings.SecretKey)
+
+	req, err = http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea85664/rotate_secret", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotFound, rr.Code)
+
+	writerMock.On("UpdateSecretKey", mock.Anything).Return(errors.New("dummy error")).Once()
+
+	req, err = http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/rotate_secret", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestRouter_RotateApplicationAAT(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	c.NoError(err)
+
+	app := router.Cache.GetApplication("5f62b7d8be3591c4dea8566f")
+	app.GatewayAAT.ClientPublicKey = "client_public_key"
+	app.GatewayAAT.ApplicationPublicKey = hex.EncodeToString(publicKey)
+	app.GatewayAAT.Address = aat.Address(publicKey)
+	app.GatewayAAT.PrivateKey = hex.EncodeToString(privateKey)
+	app.GatewayAAT.ApplicationSignature = "old_signature"
+
+	writerMock := &writerMock{}
+
+	router.Writer = writerMock
+
+	req, err := http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566f/rotate_aat", nil)
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusForbidden, rr.Code)
+
+	router.Capabilities = map[string]map[Capability]bool{"": {CapabilitySecretsRead: true}}
+
+	writerMock.On("UpdateGatewayAAT", mock.Anything).Return(nil).Once()
+
+	req, err = http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566f/rotate_aat",
+		bytes.NewBufferString(`{"version":"0.0.2"}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	// The AAT is signed again with the staked key pair
+	var rotatedApp repository.Application
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &rotatedApp))
+	c.Equal("client_public_key", rotatedApp.GatewayAAT.ClientPublicKey)
+	c.Equal("0.0.2", rotatedApp.GatewayAAT.Version)
+	c.Equal(app.GatewayAAT.ApplicationPublicKey, rotatedApp.GatewayAAT.ApplicationPublicKey)
+	c.Equal(app.GatewayAAT.Address, rotatedApp.GatewayAAT.Address)
+	c.Equal(app.GatewayAAT.PrivateKey, rotatedApp.GatewayAAT.PrivateKey)
+
+	signature, err := hex.DecodeString(rotatedApp.GatewayAAT.ApplicationSignature)
+	c.NoError(err)
+
+	hash, err := aat.Hash("0.0.2", rotatedApp.GatewayAAT.ApplicationPublicKey, "client_public_key")
+	c.NoError(err)
+	c.True(ed25519.Verify(publicKey, hash, signature))
+
+	// the cache is updated by the database notification
+	c.Equal("old_signature", app.GatewayAAT.ApplicationSignature)
+
+	// Keys not matching the staked public key are refused
+	app.GatewayAAT.ApplicationPublicKey = "a2f1b3c4d5e6f708"
+
+	req, err = http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566f/rotate_aat", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	app.GatewayAAT.ApplicationPublicKey = hex.EncodeToString(publicKey)
+
+	// Applications without key pair are refused as a new one must be staked first
+	req, err = http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/rotate_aat", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566f/rotate_aat", bytes.NewBufferString("wrong"))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	writerMock.On("UpdateGatewayAAT", mock.Anything).Return(errors.New("dummy error")).Once()
+
+	req, err = http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566f/rotate_aat", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestRouter_RestoreApplication(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	app := router.Cache.GetApplication("5f62b7d8be3591c4dea8566d")
+	app.Status = repository.AwaitingGracePeriod
+
+	writerMock := &writerMock{}
+
+	writerMock.On("RestoreApplication", mock.Anything).Return(&types.ApplicationRemoval{
+		ApplicationID:  "5f62b7d8be3591c4dea8566d",
+		PreviousStatus: repository.InService,
+		UserID:         "60ecb2bf67774900350d9c43",
+	}, nil).Once()
+
+	router.Writer = writerMock
+
+	req, err := http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566d/restore", nil)
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var restoredApp repository.Application
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &restoredApp))
+	c.Equal(repository.InService, restoredApp.Status)
+	c.Equal("60ecb2bf67774900350d9c43", restoredApp.UserID)
+
+	writerMock.On("RestoreApplication", mock.Anything).Return((*types.ApplicationRemoval)(nil), errors.New("dummy error")).Once()
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566a/restore", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea85664/restore", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotFound, rr.Code)
+}
+
+func TestRouter_RestoreLoadBalancer(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	writerMock.On("RestoreLoadBalancer", mock.Anything).Return(&types.LoadBalancerRemoval{
+		LoadBalancerID: "60ecb2bf67774900350d9c43",
+		UserID:         "60ecb2bf67774900350d9c44",
+	}, nil).Once()
+
+	router.Writer = writerMock
+
+	req, err := http.NewRequest(http.MethodPost, "/load_balancer/60ecb2bf67774900350d9c43/restore", nil)
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var restoredLB repository.LoadBalancer
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &restoredLB))
+	c.Equal("60ecb2bf67774900350d9c44", restoredLB.UserID)
+
+	writerMock.On("RestoreLoadBalancer", mock.Anything).Return((*types.LoadBalancerRemoval)(nil), errors.New("dummy error")).Once()
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	// Load balancer not removed
+	req, err = http.NewRequest(http.MethodPost, "/load_balancer/60ecb2bf67774900350d9c42/restore", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/load_balancer/60ecb2bf67774900350d9c99/restore", nil)
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotFound, rr.Code)
+
+	// Removed load balancers cannot be updated
+	req, err = http.NewRequest(http.MethodPut, "/load_balancer/60ecb2bf67774900350d9c43", bytes.NewBufferString(`{"name":"pablo"}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestRouter_TransferApplication(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	writerMock.On("TransferApplication", mock.Anything, mock.Anything).Return(nil).Once()
+
+	router.Writer = writerMock
+
+	req, err := http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566f/transfer", bytes.NewBufferString(`{"userID":"60ecb2bf67774900350d9c45"}`))
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var transferredApp repository.Application
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &transferredApp))
+	c.Equal("60ecb2bf67774900350d9c45", transferredApp.UserID)
+	c.Equal("60ecb2bf67774900350d9c44", router.Cache.GetApplication("5f62b7d8be3591c4dea8566f").UserID)
+
+	writerMock.On("TransferApplication", mock.Anything, mock.Anything).Return(errors.New("dummy error")).Once()
+
+	req, err = http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566f/transfer", bytes.NewBufferString(`{"userID":"60ecb2bf67774900350d9c45"}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea8566f/transfer", bytes.NewBufferString(`{}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/application/5f62b7d8be3591c4dea85664/transfer", bytes.NewBufferString(`{"userID":"60ecb2bf67774900350d9c45"}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotFound, rr.Code)
+}
+
+func TestRouter_TransferLoadBalancer(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	writerMock.On("TransferLoadBalancer", mock.Anything, mock.Anything).Return(nil).Once()
+
+	router.Writer = writerMock
+
+	req, err := http.NewRequest(http.MethodPost, "/load_balancer/60ecb2bf67774900350d9c42/transfer", bytes.NewBufferString(`{"userID":"60ecb2bf67774900350d9c45"}`))
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var transferredLB repository.LoadBalancer
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &transferredLB))
+	c.Equal("60ecb2bf67774900350d9c45", transferredLB.UserID)
+
+	writerMock.On("TransferLoadBalancer", mock.Anything, mock.Anything).Return(errors.New("dummy error")).Once()
+
+	req, err = http.NewRequest(http.MethodPost, "/load_balancer/60ecb2bf67774900350d9c42/transfer", bytes.NewBufferString(`{"userID":"60ecb2bf67774900350d9c45"}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/load_balancer/60ecb2bf67774900350d9c42/transfer", bytes.NewBufferString(`{"userID":""}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	// Removed load balancers cannot be transferred
+	req, err = http.NewRequest(http.MethodPost, "/load_balancer/60ecb2bf67774900350d9c43/transfer", bytes.NewBufferString(`{"userID":"60ecb2bf67774900350d9c45"}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/load_balancer/60ecb2bf67774900350d9c99/transfer", bytes.NewBufferString(`{"userID":"60ecb2bf67774900350d9c45"}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusNotFound, rr.Code)
+}
+
+func TestRouter_TransferUserEntities(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	writerMock.On("TransferUserEntities", mock.Anything, mock.Anything).Return(&types.TransferredEntities{
+		ApplicationIDs:  []string{"5f62b7d8be3591c4dea8566d"},
+		LoadBalancerIDs: []string{"60ecb2bf67774900350d9c42"},
+	}, nil).Once()
+
+	router.Writer = writerMock
+
+	req, err := http.NewRequest(http.MethodPost, "/user/60ecb2bf67774900350d9c43/transfer", bytes.NewBufferString(`{"userID":"60ecb2bf67774900350d9c45"}`))
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var transferred types.TransferredEntities
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &transferred))
+	c.Equal([]string{"5f62b7d8be3591c4dea8566d"}, transferred.ApplicationIDs)
+	c.Equal([]string{"60ecb2bf67774900350d9c42"}, transferred.LoadBalancerIDs)
+
+	writerMock.On("TransferUserEntities", mock.Anything, mock.Anything).Return((*types.TransferredEntities)(nil), errors.New("dummy error")).Once()
+
+	req, err = http.NewRequest(http.MethodPost, "/user/60ecb2bf67774900350d9c43/transfer", bytes.NewBufferString(`{"userID":"60ecb2bf67774900350d9c45"}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/user/60ecb2bf67774900350d9c43/transfer", bytes.NewBufferString(`not json`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func TestParsePayPlanQuotas(t *testing.T) {
+	c := require.New(t)
+
+	quotas, err := ParsePayPlanQuotas("")
+	c.NoError(err)
+	c.Empty(quotas)
+
+	quotas, err = ParsePayPlanQuotas("FREETIER_V0=2, PAY_AS_YOU_GO_V0=10")
+	c.NoError(err)
+	c.Equal(map[repository.PayPlanType]int{
+		repository.FreetierV0:   2,
+		repository.PayAsYouGoV0: 10,
+	}, quotas)
+
+	_, err = ParsePayPlanQuotas("NOT_A_PLAN=2")
+	c.ErrorIs(err, errInvalidQuotas)
+
+	_, err = ParsePayPlanQuotas("FREETIER_V0=-1")
+	c.ErrorIs(err, errInvalidQuotas)
+
+	_, err = ParsePayPlanQuotas("FREETIER_V0")
+	c.ErrorIs(err, errInvalidQuotas)
+}
+
+func TestParseUserQuotas(t *testing.T) {
+	c := require.New(t)
+
+	quotas, err := ParseUserQuotas("")
+	c.NoError(err)
+	c.Empty(quotas)
+
+	quotas, err = ParseUserQuotas("user1=10:5, user2=0:1")
+	c.NoError(err)
+	c.Equal(map[string]Quota{
+		"user1": {Applications: 10, LoadBalancers: 5},
+		"user2": {Applications: 0, LoadBalancers: 1},
+	}, quotas)
+
+	_, err = ParseUserQuotas("user1=10")
+	c.ErrorIs(err, errInvalidQuotas)
+
+	_, err = ParseUserQuotas("=10:5")
+	c.ErrorIs(err, errInvalidQuotas)
+
+	_, err = ParseUserQuotas("user1=ten:5")
+	c.ErrorIs(err, errInvalidQuotas)
+}
+
+func TestRouter_ApplicationQuotas(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	router.Quotas = Quotas{
+		Default:  Quota{Applications: 2},
+		PayPlans: map[repository.PayPlanType]int{repository.FreetierV0: 1},
+		Users:    map[string]Quota{"60ecb2bf67774900350d9c44": {Applications: 1}},
+	}
+
+	writerMock := &writerMock{}
+
+	writerMock.On("WriteApplication", mock.Anything).Return(&repository.Application{
+		ID: "5f62b7d8be3591c4dea85661",
+		Limit: repository.AppLimit{
+			PayPlan: repository.PayPlan{Type: repository.PayAsYouGoV0},
+		},
+	}, nil)
+
+	writerMock.On("LockUserQuota", mock.Anything).Return(nil)
+
+	router.Writer = writerMock
+
+	createApp := func(query string, app repository.Application) int {
+		appToSend, err := json.Marshal(app)
+		c.NoError(err)
+
+		req, err := http.NewRequest(http.MethodPost, "/application"+query, bytes.NewBuffer(appToSend))
+		c.NoError(err)
+
+		rr := httptest.NewRecorder()
+
+		router.Router.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	mockCounts := func(userID string, applications map[repository.PayPlanType]int) {
+		writerMock.On("ReadUserEntityCounts", userID).Return(&types.UserEntityCounts{
+			Applications: applications,
+		}, nil).Once()
+	}
+
+	freetierApp := repository.Application{
+		UserID: "60ecb2bf67774900350d9c43",
+		Limit:  repository.AppLimit{PayPlan: repository.PayPlan{Type: repository.FreetierV0}},
+	}
+
+	// User already owns the default quota of applications
+	mockCounts("60ecb2bf67774900350d9c43", map[repository.PayPlanType]int{repository.FreetierV0: 1, "": 1})
+
+	c.Equal(http.StatusConflict, createApp("", freetierApp))
+
+	// User still owns one freetier application
+	mockCounts("60ecb2bf67774900350d9c43", map[repository.PayPlanType]int{repository.FreetierV0: 1})
+
+	c.Equal(http.StatusConflict, createApp("", freetierApp))
+
+	payAsYouGoApp := freetierApp
+	payAsYouGoApp.Limit.PayPlan.Type = repository.PayAsYouGoV0
+
+	mockCounts("60ecb2bf67774900350d9c43", map[repository.PayPlanType]int{repository.FreetierV0: 1})
+
+	c.Equal(http.StatusOK, createApp("", payAsYouGoApp))
+
+	// The user quota replaces the default and pay plan quotas
+	mockCounts("60ecb2bf67774900350d9c44", map[repository.PayPlanType]int{"": 1})
+
+	c.Equal(http.StatusConflict, createApp("", repository.Application{UserID: "60ecb2bf67774900350d9c44"}))
+
+	mockCounts("60ecb2bf67774900350d9c99", map[repository.PayPlanType]int{})
+
+	c.Equal(http.StatusOK, createApp("", repository.Application{UserID: "60ecb2bf67774900350d9c99"}))
+
+	// Applications are counted in the database as the cache may not have been notified of the last ones yet
+	mockCounts("60ecb2bf67774900350d9c99", map[repository.PayPlanType]int{repository.PayAsYouGoV0: 2})
+
+	c.Equal(http.StatusConflict, createApp("", repository.Application{UserID: "60ecb2bf67774900350d9c99"}))
+
+	writerMock.AssertNumberOfCalls(t, "LockUserQuota", 6)
+
+	// Override requires the capability
+	c.Equal(http.StatusForbidden, createApp("?override_quota=true", freetierApp))
+	c.Equal(http.StatusBadRequest, createApp("?override_quota=maybe", freetierApp))
+
+	router.Capabilities = map[string]map[Capability]bool{"": {CapabilityQuotasOverride: true}}
+
+	c.Equal(http.StatusOK, createApp("?override_quota=true", freetierApp))
+
+	mockCounts("60ecb2bf67774900350d9c43", map[repository.PayPlanType]int{repository.FreetierV0: 1, "": 1})
+
+	c.Equal(http.StatusConflict, createApp("?override_quota=false", freetierApp))
+
+	writerMock.AssertNumberOfCalls(t, "LockUserQuota", 7)
+
+	// Quotas are not locked when none applies
+	router.Quotas = Quotas{}
+
+	c.Equal(http.StatusOK, createApp("", freetierApp))
+
+	writerMock.AssertNumberOfCalls(t, "LockUserQuota", 7)
+}
+
+func TestRouter_LoadBalancerQuotas(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	router.Quotas = Quotas{
+		Default: Quota{LoadBalancers: 1},
+		Users:   map[string]Quota{"60ecb2bf67774900350d9c44": {LoadBalancers: 2}},
+	}
+
+	writerMock := &writerMock{}
+
+	writerMock.On("WriteLoadBalancer", mock.Anything).Return(&repository.LoadBalancer{
+		ID: "60ecb2bf67774900350d9c41",
+	}, nil)
+
+	writerMock.On("LockUserQuota", mock.Anything).Return(nil)
+
+	writerMock.On("ReadUserEntityCounts", mock.Anything).Return(&types.UserEntityCounts{
+		LoadBalancers: 1,
+	}, nil)
+
+	router.Writer = writerMock
+
+	createLB := func(query, userID string) int {
+		req, err := http.NewRequest(http.MethodPost, "/load_balancer"+query, bytes.NewBufferString(fmt.Sprintf(`{"userID":%q}`, userID)))
+		c.NoError(err)
+
+		rr := httptest.NewRecorder()
+
+		router.Router.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	c.Equal(http.StatusConflict, createLB("", "60ecb2bf67774900350d9c43"))
+	c.Equal(http.StatusOK, createLB("", "60ecb2bf67774900350d9c44"))
+
+	router.Capabilities = map[string]map[Capability]bool{"": {CapabilityQuotasOverride: true}}
+
+	c.Equal(http.StatusOK, createLB("?override_quota=true", "60ecb2bf67774900350d9c43"))
+
+	// Failing to lock the quotas of the user fails the create
+	router.Capabilities = nil
+
+	writerMock.ExpectedCalls = nil
+
+	writerMock.On("LockUserQuota", mock.Anything).Return(errors.New("connection refused"))
+
+	c.Equal(http.StatusInternalServerError, createLB("", "60ecb2bf67774900350d9c44"))
+}
+
+func TestRouter_QuotaChanges(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	router.Quotas = Quotas{
+		Default:  Quota{Applications: 2, LoadBalancers: 1},
+		PayPlans: map[repository.PayPlanType]int{repository.PayAsYouGoV0: 1},
+	}
+
+	writerMock := &writerMock{}
+
+	writerMock.On("LockUserQuota", mock.Anything).Return(nil)
+
+	router.Writer = writerMock
+
+	send := func(method, path, body string) int {
+		req, err := http.NewRequest(method, path, bytes.NewBufferString(body))
+		c.NoError(err)
+
+		if method == http.MethodPatch {
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+		}
+
+		rr := httptest.NewRecorder()
+
+		router.Router.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	mockCounts := func(userID string, counts types.UserEntityCounts) {
+		writerMock.On("ReadUserEntityCounts", userID).Return(&counts, nil).Once()
+	}
+
+	// Moving applications to a pay plan whose quota the user reached
+	payAsYouGoCounts := types.UserEntityCounts{
+		Applications: map[repository.PayPlanType]int{repository.FreetierV0: 1, repository.PayAsYouGoV0: 1},
+	}
+
+	mockCounts("60ecb2bf67774900350d9c43", payAsYouGoCounts)
+
+	c.Equal(http.StatusConflict, send(http.MethodPut, "/application/5f62b7d8be3591c4dea8566d",
+		`{"appLimit":{"payPlan":{"planType":"PAY_AS_YOU_GO_V0"}}}`))
+
+	mockCounts("60ecb2bf67774900350d9c43", payAsYouGoCounts)
+
+	c.Equal(http.StatusConflict, send(http.MethodPatch, "/application/5f62b7d8be3591c4dea8566d",
+		`{"limit":{"payPlan":{"planType":"PAY_AS_YOU_GO_V0"}}}`))
+
+	mockCounts("60ecb2bf67774900350d9c43", types.UserEntityCounts{
+		Applications: map[repository.PayPlanType]int{repository.FreetierV0: 1, "": 1},
+	})
+
+	c.Equal(http.StatusConflict, send(http.MethodPost, "/application/bulk_update",
+		`{"applicationIDs":["5f62b7d8be3591c4dea8566d","5f62b7d8be3591c4dea8566a"],"update":{"appLimit":{"payPlan":{"planType":"PAY_AS_YOU_GO_V0"}}}}`))
+
+	// The number of applications stays the same when moving them between pay plans
+	mockCounts("60ecb2bf67774900350d9c43", types.UserEntityCounts{
+		Applications: map[repository.PayPlanType]int{repository.FreetierV0: 1, "": 1},
+	})
+
+	writerMock.On("UpdateApplication", (*time.Time)(nil)).Return(time.Now(), nil).Once()
+
+	c.Equal(http.StatusOK, send(http.MethodPut, "/application/5f62b7d8be3591c4dea8566d",
+		`{"appLimit":{"payPlan":{"planType":"PAY_AS_YOU_GO_V0"}}}`))
+
+	// Restoring applications and load balancers gives them back to the user
+	router.Cache.GetApplication("5f62b7d8be3591c4dea8566a").Status = repository.AwaitingGracePeriod
+
+	mockCounts("60ecb2bf67774900350d9c43", types.UserEntityCounts{
+		Applications: map[repository.PayPlanType]int{repository.FreetierV0: 1, "": 1},
+	})
+
+	c.Equal(http.StatusConflict, send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566a/restore", ""))
+
+	mockCounts("60ecb2bf67774900350d9c43", types.UserEntityCounts{LoadBalancers: 1})
+
+	c.Equal(http.StatusConflict, send(http.MethodPost, "/load_balancer/60ecb2bf67774900350d9c43/restore", ""))
+
+	// Transfers give the entities to the user they are transferred to
+	mockCounts("60ecb2bf67774900350d9c43", types.UserEntityCounts{
+		Applications: map[repository.PayPlanType]int{repository.FreetierV0: 2},
+	})
+
+	c.Equal(http.StatusConflict, send(http.MethodPost, "/application/5f62b7d8be3591c4dea8566f/transfer",
+		`{"userID":"60ecb2bf67774900350d9c43"}`))
+
+	mockCounts("60ecb2bf67774900350d9c44", types.UserEntityCounts{LoadBalancers: 1})
+
+	c.Equal(http.StatusConflict, send(http.MethodPost, "/load_balancer/60ecb2bf67774900350d9c42/transfer",
+		`{"userID":"60ecb2bf67774900350d9c44"}`))
+
+	mockCounts("60ecb2bf67774900350d9c44", types.UserEntityCounts{
+		Applications: map[repository.PayPlanType]int{"": 1},
+	})
+	mockCounts("60ecb2bf67774900350d9c43", types.UserEntityCounts{
+		Applications: map[repository.PayPlanType]int{repository.FreetierV0: 2},
+	})
+
+	c.Equal(http.StatusConflict, send(http.MethodPost, "/user/60ecb2bf67774900350d9c44/transfer",
+		`{"userID":"60ecb2bf67774900350d9c43"}`))
+
+	writerMock.AssertExpectations(t)
+	writerMock.AssertNumberOfCalls(t, "LockUserQuota", 9)
+}
+
+func TestRouter_CreateEndpoint(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	router.Capabilities = map[string]map[Capability]bool{"": {CapabilitySecretsRead: true}}
+
+	writerMock := &writerMock{}
+
+	writerMock.On("WriteEndpoint", mock.Anything).Return(&types.Endpoint{
+		LoadBalancer: repository.LoadBalancer{
+			ID:             "60ecb2bf67774900350d9c41",
+			UserID:         "60ecb2bf67774900350d9c45",
+			ApplicationIDs: []string{"5f62b7d8be3591c4dea8566d", "5f62b7d8be3591c4dea85661"},
+		},
+		Applications: []*repository.Application{
+			{
+				ID:     "5f62b7d8be3591c4dea85661",
+				UserID: "60ecb2bf67774900350d9c45",
+				Limit: repository.AppLimit{
+					PayPlan: repository.PayPlan{Type: repository.FreetierV0},
+				},
+				GatewaySettings: repository.GatewaySettings{SecretKey: "e1d2c3b4"},
+			},
+		},
+	}, nil).Once()
+
+	router.Writer = writerMock
+
+	rawEndpoint := `{"loadBalancer":{"userID":"60ecb2bf67774900350d9c45","applicationIDs":["5f62b7d8be3591c4dea8566d"]},` +
+		`"applications":[{"name":"pablo","payPlan":{"planType":"FREETIER_V0"}}]}`
+
+	req, err := http.NewRequest(http.MethodPost, "/endpoint", bytes.NewBufferString(rawEndpoint))
+	c.NoError(err)
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusOK, rr.Code)
+
+	var createdLB repository.LoadBalancer
+	c.NoError(json.Unmarshal(rr.Body.Bytes(), &createdLB))
+	c.Equal("60ecb2bf67774900350d9c41", createdLB.ID)
+	c.Nil(createdLB.ApplicationIDs)
+	c.Len(createdLB.Applications, 2)
+
+	// Applications already in cache are taken from it, the new ones from the writer until notified
+	c.Equal("60ecb2bf67774900350d9c43", createdLB.Applications[0].UserID)
+	c.Equal("5f62b7d8be3591c4dea85661", createdLB.Applications[1].ID)
+	c.Equal(250000, createdLB.Applications[1].Limit.PayPlan.Limit)
+	c.Equal("e1d2c3b4", createdLB.Applications[1].GatewaySettings.SecretKey)
+
+	writerMock.On("WriteEndpoint", mock.Anything).Return((*types.Endpoint)(nil), errors.New("dummy error")).Once()
+
+	req, err = http.NewRequest(http.MethodPost, "/endpoint", bytes.NewBufferString(rawEndpoint))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusInternalServerError, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/endpoint", bytes.NewBufferString(`{"loadBalancer":{"userID":"60ecb2bf67774900350d9c45"}}`))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	req, err = http.NewRequest(http.MethodPost, "/endpoint", bytes.NewBufferString("wrong"))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	// Every application of the endpoint counts towards the quota
+	router.Quotas = Quotas{Default: Quota{Applications: 3}}
+
+	writerMock.On("LockUserQuota", "60ecb2bf67774900350d9c43").Return(nil).Once()
+	writerMock.On("ReadUserEntityCounts", "60ecb2bf67774900350d9c43").Return(&types.UserEntityCounts{
+		Applications: map[repository.PayPlanType]int{repository.FreetierV0: 1, "": 1},
+	}, nil).Once()
+
+	rawEndpoint = `{"loadBalancer":{"userID":"60ecb2bf67774900350d9c43"},"applications":[{"name":"pablo"},{"name":"orlando"}]}`
+
+	req, err = http.NewRequest(http.MethodPost, "/endpoint", bytes.NewBufferString(rawEndpoint))
+	c.NoError(err)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusConflict, rr.Code)
+}
+
+func TestRouter_IdempotencyHandler(t *testing.T) {
+	c := require.New(t)
+
+	router, err := newTestRouter()
+	c.NoError(err)
+
+	writerMock := &writerMock{}
+
+	router.Writer = writerMock
+
+	transferred := &types.TransferredEntities{ApplicationIDs: []string{"5f62b7d8be3591c4dea8566d"}, LoadBalancerIDs: []string{}}
+	rawTransfer := `{"userID":"60ecb2bf67774900350d9c45"}`
+
+	newTransferRequest := func(key string) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "/user/60ecb2bf67774900350d9c43/transfer", bytes.NewBufferString(rawTransfer))
+		c.NoError(err)
+
+		req.Header.Set(idempotencyKeyHeader, key)
+
+		return req
+	}
+
+	requestHash := hashRequest(newTransferRequest("key-1"), []byte(rawTransfer))
+
+	// First request is handled and its response saved
+	writerMock.On("ReserveIdempotencyKey", "key-1", requestHash).Return((*types.IdempotentRequest)(nil), nil).Once()
+	writerMock.On("TransferUserEntities").Return(transferred, nil).Once()
+	writerMock.On("SaveIdempotentResponse", "key-1", mock.MatchedBy(func(response *types.IdempotentRequest) bool {
+		return response.StatusCode == http.StatusOK && response.RequestHash == requestHash &&
+			response.ContentType == "application/json" && bytes.Contains(response.Body, []byte("5f62b7d8be3591c4dea8566d"))
+	})).Return(nil).Once()
+
+	rr := httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, newTransferRequest("key-1"))
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.Empty(rr.Header().Get(idempotentReplayedHeader))
+
+	firstBody := rr.Body.Bytes()
+
+	// Retries replay the saved response without handling the request again
+	writerMock.On("ReserveIdempotencyKey", "key-1", requestHash).Return(&types.IdempotentRequest{
+		RequestHash: requestHash,
+		StatusCode:  http.StatusOK,
+		ContentType: "application/json",
+		Body:        firstBody,
+	}, nil).Once()
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, newTransferRequest("key-1"))
+
+	c.Equal(http.StatusOK, rr.Code)
+	c.Equal("true", rr.Header().Get(idempotentReplayedHeader))
+	c.Equal(firstBody, rr.Body.Bytes())
+
+	// The key was used for another request
+	writerMock.On("ReserveIdempotencyKey", "key-1", requestHash).Return(&types.IdempotentRequest{
+		RequestHash: "another-hash",
+		StatusCode:  http.StatusOK,
+	}, nil).Once()
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, newTransferRequest("key-1"))
+
+	c.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	// The first request is still being handled
+	writerMock.On("ReserveIdempotencyKey", "key-1", requestHash).Return(&types.IdempotentRequest{
+		RequestHash: requestHash,
+	}, nil).Once()
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, newTransferRequest("key-1"))
+
+	c.Equal(http.StatusConflict, rr.Code)
+
+	// Server errors are not saved so the request can be retried
+	writerMock.On("ReserveIdempotencyKey", "key-2", mock.Anything).Return((*types.IdempotentRequest)(nil), nil).Once()
+	writerMock.On("WriteLoadBalancer", mock.Anything).Return((*repository.LoadBalancer)(nil), errors.New("dummy error")).Once()
+	writerMock.On("ReleaseIdempotencyKey", "key-2").Return(nil).Once()
+
+	req, err := http.NewRequest(http.MethodPost, "/load_balancer", bytes.NewBufferString(`{"name":"pablo"}`))
+	c.NoError(err)
+
+	req.Header.Set(idempotencyKeyHeader, "key-2")
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, req)
+
+	c.Equal(http.StatusInternalServerError, rr.Code)
+
+	writerMock.On("ReserveIdempotencyKey", "key-3", requestHash).Return((*types.IdempotentRequest)(nil), errors.New("dummy error")).Once()
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, newTransferRequest("key-3"))
+
+	c.Equal(http.StatusInternalServerError, rr.Code)
+
+	rr = httptest.NewRecorder()
+
+	router.Router.ServeHTTP(rr, newTransferRequest(strings.Repeat("k", maxIdempotencyKeyLength+1)))
+
+	c.Equal(http.StatusBadRequest, rr.Code)
+
+	writerMock.AssertExpectations(t)
+}
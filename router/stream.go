@@ -0,0 +1,98 @@
+package router
+
+import (
+	"bufio"
+	"errors"
+	"net/http"
+
+	jsonresponse "github.com/pokt-foundation/utils-go/json-response"
+)
+
+// streamBufferSize is the amount of encoded items buffered before they are written to the client
+const streamBufferSize = 32 * 1024
+
+// statusWriter holds back the status code until the first write, so a list failing to
+// encode before anything reached the client can still be answered with an error
+type statusWriter struct {
+	http.ResponseWriter
+	code      int
+	committed bool
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	if !sw.committed {
+		sw.committed = true
+		sw.ResponseWriter.WriteHeader(sw.code)
+	}
+
+	return sw.ResponseWriter.Write(b)
+}
+
+// respondWithJSONList writes the list as a JSON array encoding one item at a time with the given
+// encoder so big lists are never held in memory as a whole, with a zero encoder the output is the
+// same json.Marshal gives for the slice. An item failing to encode before the first buffer is
+// written gets an error response instead, once the status is sent the array is left unterminated
+// so the client never takes a truncated list for a complete one
+func respondWithJSONList[T any](w http.ResponseWriter, code int, items []T, enc encoder) error {
+	w.Header().Set("Content-Type", "application/json")
+
+	sw := &statusWriter{ResponseWriter: w, code: code}
+
+	err := writeJSONList(sw, items, enc)
+	if err == nil || sw.committed {
+		return err
+	}
+
+	if errors.Is(err, errFieldsNotSupported) {
+		jsonresponse.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	jsonresponse.RespondWithError(w, http.StatusInternalServerError, err.Error())
+
+	return err
+}
+
+func writeJSONList[T any](sw *statusWriter, items []T, enc encoder) error {
+	buffer := bufio.NewWriterSize(sw, streamBufferSize)
+
+	if items == nil {
+		_, err := buffer.WriteString("null")
+		if err != nil {
+			return err
+		}
+
+		return buffer.Flush()
+	}
+
+	err := buffer.WriteByte('[')
+	if err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		if i > 0 {
+			err = buffer.WriteByte(',')
+			if err != nil {
+				return err
+			}
+		}
+
+		rawItem, err := enc.encode(item)
+		if err != nil {
+			return err
+		}
+
+		_, err = buffer.Write(rawItem)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = buffer.WriteByte(']')
+	if err != nil {
+		return err
+	}
+
+	return buffer.Flush()
+}
@@ -0,0 +1,674 @@
+// Package types holds the PHD specific input and output types not available in the portal-api-go repository package
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pokt-foundation/portal-api-go/repository"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	ErrInvalidLogLimitBlocks      = errors.New("log limit blocks cannot be negative")
+	ErrInvalidRequestTimeout      = errors.New("request timeout cannot be negative")
+	ErrMissingAltruist            = errors.New("missing altruist")
+	ErrInvalidWhitelistType       = errors.New("invalid whitelist type")
+	ErrNoWhitelistValues          = errors.New("no whitelist values")
+	ErrMissingWhitelistBlockchain = errors.New("missing whitelist blockchain id")
+	ErrInvalidContract            = errors.New("invalid whitelist contract")
+	ErrInvalidContractChecksum    = errors.New("whitelist contract does not match its checksum")
+	ErrInvalidMethod              = errors.New("invalid whitelist method")
+	ErrNoApplicationsSelected     = errors.New("either application IDs or a filter are needed")
+	ErrApplicationsSelectedTwice  = errors.New("application IDs and filter cannot be used together")
+	ErrEmptyApplicationFilter     = errors.New("application filter cannot be empty")
+	ErrBulkNameUpdate             = errors.New("names cannot be bulk updated")
+	ErrBulkRemove                 = errors.New("applications cannot be bulk removed")
+	ErrBulkGatewaySettingsUpdate  = errors.New("secret keys and whitelists cannot be bulk updated")
+	ErrApplicationChanged         = errors.New("application changed since it was read")
+	ErrLoadBalancerChanged        = errors.New("load balancer changed since it was read")
+	ErrMissingApplicationID       = errors.New("missing application id")
+	ErrNoUsageStateToUpdate       = errors.New("no usage state to update")
+	ErrInvalidUsageThreshold      = errors.New("invalid usage threshold")
+	ErrResetWithUsageState        = errors.New("usage state cannot be set on a reset")
+	ErrApplicationNotFound        = errors.New("application not found")
+)
+
+// UpdateBlockchain struct holding the fields a blockchain update replaces, empty values clear the stored ones
+type UpdateBlockchain struct {
+	Altruist          string                       `json:"altruist,omitempty"`
+	ChainID           string                       `json:"chainID,omitempty"`
+	EnforceResult     string                       `json:"enforceResult,omitempty"`
+	BlockchainAliases []string                     `json:"blockchainAliases,omitempty"`
+	LogLimitBlocks    int                          `json:"logLimitBlocks,omitempty"`
+	RequestTimeout    int                          `json:"requestTimeout,omitempty"`
+	SyncCheckOptions  *repository.SyncCheckOptions `json:"syncCheckOptions,omitempty"`
+}
+
+func (u *UpdateBlockchain) Validate() error {
+	if u == nil {
+		return repository.ErrNoFieldsToUpdate
+	}
+	// every field is replaced so the altruist, which relays cannot go without, must always be given
+	if u.Altruist == "" {
+		return ErrMissingAltruist
+	}
+	if u.LogLimitBlocks < 0 {
+		return ErrInvalidLogLimitBlocks
+	}
+	if u.RequestTimeout < 0 {
+		return ErrInvalidRequestTimeout
+	}
+	return nil
+}
+
+// PublicKeys struct holding the application public keys to look up
+type PublicKeys struct {
+	PublicKeys []string `json:"publicKeys"`
+}
+
+// IDs struct holding the entity IDs to look up
+type IDs struct {
+	IDs []string `json:"ids"`
+}
+
+// RotateAAT struct holding the client public key and version the gateway AAT is reissued for,
+// empty fields keep the ones of the current AAT
+type RotateAAT struct {
+	ClientPublicKey string `json:"clientPublicKey"`
+	Version         string `json:"version"`
+}
+
+// BatchApplications struct holding the applications found on a batch lookup
+// and the keys that did not match any application
+type BatchApplications struct {
+	Applications []*repository.Application `json:"applications"`
+	Missing      []string                  `json:"missing"`
+}
+
+// BatchLoadBalancers struct holding the load balancers found on a batch lookup
+// and the IDs that did not match any load balancer
+type BatchLoadBalancers struct {
+	LoadBalancers []*repository.LoadBalancer `json:"loadBalancers"`
+	Missing       []string                   `json:"missing"`
+}
+
+// Changes struct holding the entities changed since a given version, when Resync is set
+// the changes hold every entity and previously synced state must be replaced
+type Changes struct {
+	Version       string                     `json:"version"`
+	Resync        bool                       `json:"resync"`
+	Applications  []*repository.Application  `json:"applications"`
+	LoadBalancers []*repository.LoadBalancer `json:"loadBalancers"`
+	Blockchains   []*repository.Blockchain   `json:"blockchains"`
+	Removed       []Tombstone                `json:"removed"`
+}
+
+// Tombstone struct identifying an entity removed since a given version
+type Tombstone struct {
+	Collection string `json:"collection"`
+	ID         string `json:"id"`
+}
+
+// ApplicationRemoval struct holding the state an application had before being removed,
+// kept while the application awaits its grace period so the removal can be undone
+type ApplicationRemoval struct {
+	ApplicationID  string               `json:"applicationID"`
+	PreviousStatus repository.AppStatus `json:"previousStatus"`
+	UserID         string               `json:"userID"`
+	RemovedAt      time.Time            `json:"removedAt"`
+}
+
+// LoadBalancerRemoval struct holding the owner a load balancer had before being removed,
+// kept until the load balancer is restored or purged
+type LoadBalancerRemoval struct {
+	LoadBalancerID string    `json:"loadBalancerID"`
+	UserID         string    `json:"userID"`
+	RemovedAt      time.Time `json:"removedAt"`
+}
+
+// LoadBalancer struct holding a load balancer along with the time it was removed, nil while it is not removed
+type LoadBalancer struct {
+	*repository.LoadBalancer
+	RemovedAt *time.Time `json:"removedAt,omitempty"`
+}
+
+// Transfer struct holding the user to give the ownership of entities to
+type Transfer struct {
+	UserID string `json:"userID"`
+}
+
+// TransferredEntities struct holding the entities whose ownership was transferred
+type TransferredEntities struct {
+	ApplicationIDs  []string `json:"applicationIDs"`
+	LoadBalancerIDs []string `json:"loadBalancerIDs"`
+}
+
+// UserEntityCounts struct holding how many applications of each pay plan and load balancers a user owns
+type UserEntityCounts struct {
+	Applications  map[repository.PayPlanType]int
+	LoadBalancers int
+}
+
+// UserSummary struct holding the stats of the entities owned by a user, the total daily
+// limit adds the limits of the applications not awaiting their grace period
+type UserSummary struct {
+	UserID                string                         `json:"userID"`
+	ApplicationsCount     int                            `json:"applicationsCount"`
+	LoadBalancersCount    int                            `json:"loadBalancersCount"`
+	ApplicationsByStatus  map[repository.AppStatus]int   `json:"applicationsByStatus"`
+	ApplicationsByPayPlan map[repository.PayPlanType]int `json:"applicationsByPayPlan"`
+	TotalDailyLimit       int                            `json:"totalDailyLimit"`
+}
+
+// User struct holding every application and load balancer owned by a user along with their summary
+type User struct {
+	Summary       UserSummary                `json:"summary"`
+	Applications  []*repository.Application  `json:"applications"`
+	LoadBalancers []*repository.LoadBalancer `json:"loadBalancers"`
+}
+
+// Endpoint struct holding a load balancer and the applications to create along with it,
+// the applications are created for the load balancer user and added to its applications
+type Endpoint struct {
+	LoadBalancer repository.LoadBalancer   `json:"loadBalancer"`
+	Applications []*repository.Application `json:"applications"`
+}
+
+// IdempotentRequest struct holding what is saved for an idempotency key, a zero status
+// code means the first request with the key is still being handled
+type IdempotentRequest struct {
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// ApplicationFilter struct holding the fields the applications to select must match, empty fields match every application
+type ApplicationFilter struct {
+	UserID  string                 `json:"userID,omitempty"`
+	PayPlan repository.PayPlanType `json:"payPlan,omitempty"`
+	Status  repository.AppStatus   `json:"status,omitempty"`
+}
+
+// Match returns whether the application matches every field set on the filter
+func (f *ApplicationFilter) Match(app *repository.Application) bool {
+	return (f.UserID == "" || app.UserID == f.UserID) &&
+		(f.PayPlan == "" || app.Limit.PayPlan.Type == f.PayPlan) &&
+		(f.Status == "" || app.Status == f.Status)
+}
+
+// BulkUpdateApplications struct holding the applications to update, either by ID or by filter, and the update
+// applied to all of them, on a dry run nothing is saved and only the changes that would be made are reported
+type BulkUpdateApplications struct {
+	ApplicationIDs []string                     `json:"applicationIDs,omitempty"`
+	Filter         *ApplicationFilter           `json:"filter,omitempty"`
+	Update         repository.UpdateApplication `json:"update"`
+	DryRun         bool                         `json:"dryRun,omitempty"`
+}
+
+func (b *BulkUpdateApplications) Validate() error {
+	if b == nil || (len(b.ApplicationIDs) == 0 && b.Filter == nil) {
+		return ErrNoApplicationsSelected
+	}
+	if len(b.ApplicationIDs) > 0 && b.Filter != nil {
+		return ErrApplicationsSelectedTwice
+	}
+	// an empty filter would select every application
+	if b.Filter != nil && *b.Filter == (ApplicationFilter{}) {
+		return ErrEmptyApplicationFilter
+	}
+	if b.Update.Name != "" {
+		return ErrBulkNameUpdate
+	}
+	if b.Update.Remove {
+		return ErrBulkRemove
+	}
+	// the gateway settings of each application are kept, only whether the secret key is required can change
+	if settings := b.Update.GatewaySettings; settings != nil && (settings.SecretKey != "" ||
+		len(settings.WhitelistOrigins) > 0 || len(settings.WhitelistUserAgents) > 0 ||
+		len(settings.WhitelistBlockchains) > 0 || len(settings.WhitelistContracts) > 0 ||
+		len(settings.WhitelistMethods) > 0) {
+		return ErrBulkGatewaySettingsUpdate
+	}
+	if b.Update.Status == "" && b.Update.FirstDateSurpassed.IsZero() && b.Update.Limit == nil &&
+		b.Update.GatewaySettings == nil && b.Update.NotificationSettings == nil {
+		return repository.ErrNoFieldsToUpdate
+	}
+	return b.Update.Validate()
+}
+
+// BulkUpdateResult struct holding the applications changed by a bulk update, the selected
+// ones the update leaves as they are and the application IDs that did not match any application
+type BulkUpdateResult struct {
+	DryRun    bool                     `json:"dryRun"`
+	Updated   []BulkUpdatedApplication `json:"updated"`
+	Unchanged []string                 `json:"unchanged"`
+	Missing   []string                 `json:"missing"`
+}
+
+// BulkUpdatedApplication struct holding the fields a bulk update changes on an application
+type BulkUpdatedApplication struct {
+	ApplicationID string                 `json:"applicationID"`
+	Changes       map[string]FieldChange `json:"changes"`
+}
+
+// FieldChange struct holding the JSON value of a field before and after a change
+type FieldChange struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// UsageThreshold is a share of the daily limit of an application whose reach is notified,
+// named as the matching field of the notification settings
+type UsageThreshold string
+
+const (
+	ThresholdQuarter       UsageThreshold = "quarter"
+	ThresholdHalf          UsageThreshold = "half"
+	ThresholdThreeQuarters UsageThreshold = "threeQuarters"
+	ThresholdFull          UsageThreshold = "full"
+)
+
+// ValidUsageThresholds are the thresholds a UsageStateUpdate accepts
+var ValidUsageThresholds = map[UsageThreshold]bool{
+	ThresholdQuarter:       true,
+	ThresholdHalf:          true,
+	ThresholdThreeQuarters: true,
+	ThresholdFull:          true,
+}
+
+// UsageState struct holding the limit related state of an application
+type UsageState struct {
+	ApplicationID      string           `json:"applicationID"`
+	FirstDateSurpassed time.Time        `json:"firstDateSurpassed,omitempty"`
+	ThresholdsReached  []UsageThreshold `json:"thresholdsReached"`
+}
+
+// UsageStateUpdate struct holding the limit related state to set on an application, the thresholds are added to
+// the ones already reached, a reset clears both the first date surpassed and the thresholds reached
+type UsageStateUpdate struct {
+	ApplicationID      string           `json:"applicationID"`
+	FirstDateSurpassed time.Time        `json:"firstDateSurpassed,omitempty"`
+	ThresholdsReached  []UsageThreshold `json:"thresholdsReached,omitempty"`
+	Reset              bool             `json:"reset,omitempty"`
+}
+
+func (u *UsageStateUpdate) Validate() error {
+	if u == nil {
+		return ErrNoUsageStateToUpdate
+	}
+	if u.ApplicationID == "" {
+		return ErrMissingApplicationID
+	}
+	if u.Reset && (!u.FirstDateSurpassed.IsZero() || len(u.ThresholdsReached) > 0) {
+		return ErrResetWithUsageState
+	}
+	if !u.Reset && u.FirstDateSurpassed.IsZero() && len(u.ThresholdsReached) == 0 {
+		return ErrNoUsageStateToUpdate
+	}
+	for _, threshold := range u.ThresholdsReached {
+		if !ValidUsageThresholds[threshold] {
+			return fmt.Errorf("%w: %s", ErrInvalidUsageThreshold, threshold)
+		}
+	}
+	return nil
+}
+
+// UsageStateUpdates struct holding the usage state updates to apply on a batch
+type UsageStateUpdates struct {
+	Updates []*UsageStateUpdate `json:"updates"`
+}
+
+// UsageStateResult struct holding the outcome of a usage state update of a batch, the status is the
+// HTTP status the update would have on its own and the usage state is the one left once applied
+type UsageStateResult struct {
+	ApplicationID string      `json:"applicationID"`
+	Status        int         `json:"status"`
+	Error         string      `json:"error,omitempty"`
+	UsageState    *UsageState `json:"usageState,omitempty"`
+}
+
+// WhitelistMigration struct holding the number of applications whose stored whitelists were rewritten
+// and the reason the whitelists of each application left untouched are not valid
+type WhitelistMigration struct {
+	Migrated int
+	Invalid  map[string]string
+}
+
+// WhitelistType is the gateway settings whitelist a WhitelistChange applies to
+type WhitelistType string
+
+const (
+	WhitelistOrigins     WhitelistType = "origins"
+	WhitelistUserAgents  WhitelistType = "user_agents"
+	WhitelistBlockchains WhitelistType = "blockchains"
+	WhitelistContracts   WhitelistType = "contracts"
+	WhitelistMethods     WhitelistType = "methods"
+)
+
+// WhitelistChange struct holding the values to add to or remove from a whitelist of the gateway
+// settings, the blockchain ID is only used by the contracts and methods whitelists
+type WhitelistChange struct {
+	Type         WhitelistType
+	BlockchainID string
+	Values       []string
+	Remove       bool
+}
+
+func (c *WhitelistChange) Validate() error {
+	if c == nil {
+		return ErrNoWhitelistValues
+	}
+
+	switch c.Type {
+	case WhitelistOrigins, WhitelistUserAgents, WhitelistBlockchains:
+		if len(c.Values) == 0 {
+			return ErrNoWhitelistValues
+		}
+	case WhitelistContracts, WhitelistMethods:
+		if c.BlockchainID == "" {
+			return ErrMissingWhitelistBlockchain
+		}
+		// removing no contracts or methods removes every one of the blockchain
+		if len(c.Values) == 0 && !c.Remove {
+			return ErrNoWhitelistValues
+		}
+	default:
+		return ErrInvalidWhitelistType
+	}
+
+	for _, value := range c.Values {
+		if value == "" {
+			return ErrNoWhitelistValues
+		}
+
+		var err error
+
+		switch c.Type {
+		case WhitelistContracts:
+			_, err = NormalizeContract(value)
+		case WhitelistMethods:
+			err = ValidateMethod(value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Apply adds or removes the values on the whitelist of the settings, values already whitelisted are not
+// added twice and the contracts or methods of a blockchain left without values are dropped, changing the
+// contracts or methods normalizes them as NormalizeWhitelists does
+func (c *WhitelistChange) Apply(settings *repository.GatewaySettings) error {
+	switch c.Type {
+	case WhitelistOrigins:
+		settings.WhitelistOrigins = c.applyValues(settings.WhitelistOrigins)
+	case WhitelistUserAgents:
+		settings.WhitelistUserAgents = c.applyValues(settings.WhitelistUserAgents)
+	case WhitelistBlockchains:
+		settings.WhitelistBlockchains = c.applyValues(settings.WhitelistBlockchains)
+	case WhitelistContracts, WhitelistMethods:
+		err := NormalizeWhitelists(settings)
+		if err != nil {
+			return err
+		}
+
+		normalized := *c
+		normalized.Values = make([]string, 0, len(c.Values))
+
+		for _, value := range c.Values {
+			value = strings.TrimSpace(value)
+
+			if c.Type == WhitelistContracts {
+				value, err = NormalizeContract(value)
+				if err != nil {
+					return err
+				}
+			}
+
+			normalized.Values = append(normalized.Values, value)
+		}
+
+		if c.Type == WhitelistContracts {
+			settings.WhitelistContracts = normalized.applyContracts(settings.WhitelistContracts)
+		} else {
+			settings.WhitelistMethods = normalized.applyMethods(settings.WhitelistMethods)
+		}
+	default:
+		return ErrInvalidWhitelistType
+	}
+
+	return nil
+}
+
+func (c *WhitelistChange) applyValues(values []string) []string {
+	if c.Remove {
+		var kept []string
+
+		for _, value := range values {
+			if len(c.Values) > 0 && !contains(c.Values, value) {
+				kept = append(kept, value)
+			}
+		}
+
+		return kept
+	}
+
+	values = append([]string{}, values...)
+
+	for _, value := range c.Values {
+		if !contains(values, value) {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func (c *WhitelistChange) applyContracts(contracts []repository.WhitelistContract) []repository.WhitelistContract {
+	var applied []repository.WhitelistContract
+
+	added := false
+
+	for _, contract := range contracts {
+		if contract.BlockchainID == c.BlockchainID && (c.Remove || !added) {
+			contract.Contracts = c.applyValues(contract.Contracts)
+			added = true
+
+			if len(contract.Contracts) == 0 {
+				continue
+			}
+		}
+
+		applied = append(applied, contract)
+	}
+
+	if !added && !c.Remove {
+		applied = append(applied, repository.WhitelistContract{BlockchainID: c.BlockchainID, Contracts: c.applyValues(nil)})
+	}
+
+	return applied
+}
+
+func (c *WhitelistChange) applyMethods(methods []repository.WhitelistMethod) []repository.WhitelistMethod {
+	var applied []repository.WhitelistMethod
+
+	added := false
+
+	for _, method := range methods {
+		if method.BlockchainID == c.BlockchainID && (c.Remove || !added) {
+			method.Methods = c.applyValues(method.Methods)
+			added = true
+
+			if len(method.Methods) == 0 {
+				continue
+			}
+		}
+
+		applied = append(applied, method)
+	}
+
+	if !added && !c.Remove {
+		applied = append(applied, repository.WhitelistMethod{BlockchainID: c.BlockchainID, Methods: c.applyValues(nil)})
+	}
+
+	return applied
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	// contractPattern matches the addresses of every supported chain, hex addresses are checked further
+	contractPattern   = regexp.MustCompile(`^[A-Za-z0-9_.:-]+$`)
+	hexAddressPattern = regexp.MustCompile(`^0[xX][0-9a-fA-F]{40}$`)
+	// methodPattern matches both JSON-RPC method names and the REST paths of non EVM chains
+	methodPattern = regexp.MustCompile(`^[A-Za-z0-9_./:-]+$`)
+)
+
+// NormalizeContract returns the contract as it is whitelisted, 20 bytes hex addresses are returned with their
+// EIP-55 checksum and rejected when written in mixed case not matching it
+func NormalizeContract(contract string) (string, error) {
+	contract = strings.TrimSpace(contract)
+
+	if !contractPattern.MatchString(contract) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidContract, contract)
+	}
+
+	if !hexAddressPattern.MatchString(contract) {
+		return contract, nil
+	}
+
+	address := contract[2:]
+	checksummed := checksumAddress(address)
+
+	if address != strings.ToLower(address) && address != strings.ToUpper(address) && "0x"+address != checksummed {
+		return "", fmt.Errorf("%w: %q", ErrInvalidContractChecksum, contract)
+	}
+
+	return checksummed, nil
+}
+
+// checksumAddress returns the hex address with the EIP-55 checksum, each letter is upper cased
+// when the matching nibble of the keccak256 hash of the lower cased address is 8 or more
+func checksumAddress(address string) string {
+	address = strings.ToLower(address)
+
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(address))
+	digest := hex.EncodeToString(hash.Sum(nil))
+
+	checksummed := []byte(address)
+	for i, char := range checksummed {
+		if char >= 'a' && digest[i] >= '8' {
+			checksummed[i] = char - 'a' + 'A'
+		}
+	}
+
+	return "0x" + string(checksummed)
+}
+
+// ValidateMethod returns an error when the method cannot be whitelisted
+func ValidateMethod(method string) error {
+	if !methodPattern.MatchString(strings.TrimSpace(method)) {
+		return fmt.Errorf("%w: %q", ErrInvalidMethod, method)
+	}
+
+	return nil
+}
+
+// NormalizeWhitelists validates the contracts and methods whitelists of the settings and stores them as
+// a single entry for each blockchain, in the order they first appear, without duplicates or blockchains
+// left without values and with the hex addresses checksummed
+func NormalizeWhitelists(settings *repository.GatewaySettings) error {
+	var contracts []repository.WhitelistContract
+
+	contractsIndex := make(map[string]int)
+
+	for _, contract := range settings.WhitelistContracts {
+		blockchainID := strings.TrimSpace(contract.BlockchainID)
+		if blockchainID == "" {
+			return ErrMissingWhitelistBlockchain
+		}
+
+		i, ok := contractsIndex[blockchainID]
+		if !ok {
+			i = len(contracts)
+			contractsIndex[blockchainID] = i
+			contracts = append(contracts, repository.WhitelistContract{BlockchainID: blockchainID})
+		}
+
+		for _, address := range contract.Contracts {
+			address, err := NormalizeContract(address)
+			if err != nil {
+				return err
+			}
+
+			if !contains(contracts[i].Contracts, address) {
+				contracts[i].Contracts = append(contracts[i].Contracts, address)
+			}
+		}
+	}
+
+	var methods []repository.WhitelistMethod
+
+	methodsIndex := make(map[string]int)
+
+	for _, method := range settings.WhitelistMethods {
+		blockchainID := strings.TrimSpace(method.BlockchainID)
+		if blockchainID == "" {
+			return ErrMissingWhitelistBlockchain
+		}
+
+		i, ok := methodsIndex[blockchainID]
+		if !ok {
+			i = len(methods)
+			methodsIndex[blockchainID] = i
+			methods = append(methods, repository.WhitelistMethod{BlockchainID: blockchainID})
+		}
+
+		for _, name := range method.Methods {
+			name = strings.TrimSpace(name)
+
+			err := ValidateMethod(name)
+			if err != nil {
+				return err
+			}
+
+			if !contains(methods[i].Methods, name) {
+				methods[i].Methods = append(methods[i].Methods, name)
+			}
+		}
+	}
+
+	settings.WhitelistContracts = nil
+	for _, contract := range contracts {
+		if len(contract.Contracts) > 0 {
+			settings.WhitelistContracts = append(settings.WhitelistContracts, contract)
+		}
+	}
+
+	settings.WhitelistMethods = nil
+	for _, method := range methods {
+		if len(method.Methods) > 0 {
+			settings.WhitelistMethods = append(settings.WhitelistMethods, method)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package types
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-api-go/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhitelistChange_Validate(t *testing.T) {
+	c := require.New(t)
+
+	c.NoError((&WhitelistChange{Type: WhitelistOrigins, Values: []string{"https://pokt.network"}}).Validate())
+	c.NoError((&WhitelistChange{Type: WhitelistContracts, BlockchainID: "0021", Remove: true}).Validate())
+	c.ErrorIs((&WhitelistChange{Type: "wrong", Values: []string{"value"}}).Validate(), ErrInvalidWhitelistType)
+	c.ErrorIs((&WhitelistChange{Type: WhitelistOrigins}).Validate(), ErrNoWhitelistValues)
+	c.ErrorIs((&WhitelistChange{Type: WhitelistUserAgents, Values: []string{""}}).Validate(), ErrNoWhitelistValues)
+	c.ErrorIs((&WhitelistChange{Type: WhitelistMethods, Values: []string{"eth_call"}}).Validate(), ErrMissingWhitelistBlockchain)
+	c.ErrorIs((&WhitelistChange{Type: WhitelistMethods, BlockchainID: "0021"}).Validate(), ErrNoWhitelistValues)
+	c.ErrorIs((&WhitelistChange{Type: WhitelistMethods, BlockchainID: "0021", Values: []string{"eth call"}}).Validate(), ErrInvalidMethod)
+	c.ErrorIs((&WhitelistChange{Type: WhitelistContracts, BlockchainID: "0021", Values: []string{"0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}}).Validate(), ErrInvalidContractChecksum)
+}
+
+func TestWhitelistChange_Apply(t *testing.T) {
+	c := require.New(t)
+
+	settings := repository.GatewaySettings{
+		WhitelistOrigins: []string{"origin-1"},
+		WhitelistMethods: []repository.WhitelistMethod{
+			{BlockchainID: "0021", Methods: []string{"eth_call"}},
+			{BlockchainID: "0022", Methods: []string{"eth_call", "eth_chainId"}},
+		},
+	}
+
+	c.NoError((&WhitelistChange{Type: WhitelistOrigins, Values: []string{"origin-1", "origin-2"}}).Apply(&settings))
+	c.Equal([]string{"origin-1", "origin-2"}, settings.WhitelistOrigins)
+
+	c.NoError((&WhitelistChange{Type: WhitelistOrigins, Values: []string{"origin-1"}, Remove: true}).Apply(&settings))
+	c.Equal([]string{"origin-2"}, settings.WhitelistOrigins)
+
+	c.NoError((&WhitelistChange{Type: WhitelistOrigins, Values: []string{"origin-2"}, Remove: true}).Apply(&settings))
+	c.Empty(settings.WhitelistOrigins)
+
+	c.NoError((&WhitelistChange{Type: WhitelistContracts, BlockchainID: "0021", Values: []string{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"}}).Apply(&settings))
+	c.Equal([]repository.WhitelistContract{{BlockchainID: "0021", Contracts: []string{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}}}, settings.WhitelistContracts)
+
+	c.NoError((&WhitelistChange{Type: WhitelistContracts, BlockchainID: "0021", Values: []string{"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED"}, Remove: true}).Apply(&settings))
+	c.Empty(settings.WhitelistContracts)
+
+	c.NoError((&WhitelistChange{Type: WhitelistMethods, BlockchainID: "0021", Values: []string{"eth_getLogs"}}).Apply(&settings))
+	c.Equal([]string{"eth_call", "eth_getLogs"}, settings.WhitelistMethods[0].Methods)
+
+	c.NoError((&WhitelistChange{Type: WhitelistMethods, BlockchainID: "0022", Values: []string{"eth_call"}, Remove: true}).Apply(&settings))
+	c.Equal([]string{"eth_chainId"}, settings.WhitelistMethods[1].Methods)
+
+	c.NoError((&WhitelistChange{Type: WhitelistMethods, BlockchainID: "0021", Remove: true}).Apply(&settings))
+	c.Equal([]repository.WhitelistMethod{{BlockchainID: "0022", Methods: []string{"eth_chainId"}}}, settings.WhitelistMethods)
+}
+
+func TestNormalizeContract(t *testing.T) {
+	c := require.New(t)
+
+	// checksummed addresses from EIP-55
+	for _, address := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		normalized, err := NormalizeContract(address)
+		c.NoError(err)
+		c.Equal(address, normalized)
+
+		normalized, err = NormalizeContract(" " + strings.ToLower(address) + " ")
+		c.NoError(err)
+		c.Equal(address, normalized)
+	}
+
+	normalized, err := NormalizeContract("pokt1contract")
+	c.NoError(err)
+	c.Equal("pokt1contract", normalized)
+
+	_, err = NormalizeContract("0x5aaeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	c.ErrorIs(err, ErrInvalidContractChecksum)
+
+	_, err = NormalizeContract(`0x5aaeb"`)
+	c.ErrorIs(err, ErrInvalidContract)
+
+	_, err = NormalizeContract("")
+	c.ErrorIs(err, ErrInvalidContract)
+}
+
+func TestNormalizeWhitelists(t *testing.T) {
+	c := require.New(t)
+
+	settings := repository.GatewaySettings{
+		WhitelistContracts: []repository.WhitelistContract{
+			{BlockchainID: "0021", Contracts: []string{"0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359"}},
+			{BlockchainID: "0022", Contracts: []string{}},
+			{BlockchainID: " 0021", Contracts: []string{"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", "pokt1contract"}},
+		},
+		WhitelistMethods: []repository.WhitelistMethod{
+			{BlockchainID: "0021", Methods: []string{"eth_call"}},
+			{BlockchainID: "0021", Methods: []string{" eth_call", "eth_getLogs"}},
+			{BlockchainID: "0001", Methods: []string{"/v1/query/height"}},
+		},
+	}
+
+	c.NoError(NormalizeWhitelists(&settings))
+	c.Equal([]repository.WhitelistContract{
+		{BlockchainID: "0021", Contracts: []string{"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", "pokt1contract"}},
+	}, settings.WhitelistContracts)
+	c.Equal([]repository.WhitelistMethod{
+		{BlockchainID: "0021", Methods: []string{"eth_call", "eth_getLogs"}},
+		{BlockchainID: "0001", Methods: []string{"/v1/query/height"}},
+	}, settings.WhitelistMethods)
+
+	c.ErrorIs(NormalizeWhitelists(&repository.GatewaySettings{
+		WhitelistMethods: []repository.WhitelistMethod{{Methods: []string{"eth_call"}}},
+	}), ErrMissingWhitelistBlockchain)
+	c.ErrorIs(NormalizeWhitelists(&repository.GatewaySettings{
+		WhitelistMethods: []repository.WhitelistMethod{{BlockchainID: "0021", Methods: []string{"eth call"}}},
+	}), ErrInvalidMethod)
+}
+
+func TestUsageStateUpdate_Validate(t *testing.T) {
+	c := require.New(t)
+
+	c.NoError((&UsageStateUpdate{ApplicationID: "app", FirstDateSurpassed: time.Now()}).Validate())
+	c.NoError((&UsageStateUpdate{ApplicationID: "app", ThresholdsReached: []UsageThreshold{ThresholdQuarter, ThresholdFull}}).Validate())
+	c.NoError((&UsageStateUpdate{ApplicationID: "app", Reset: true}).Validate())
+	c.ErrorIs((*UsageStateUpdate)(nil).Validate(), ErrNoUsageStateToUpdate)
+	c.ErrorIs((&UsageStateUpdate{Reset: true}).Validate(), ErrMissingApplicationID)
+	c.ErrorIs((&UsageStateUpdate{ApplicationID: "app"}).Validate(), ErrNoUsageStateToUpdate)
+	c.ErrorIs((&UsageStateUpdate{ApplicationID: "app", Reset: true, FirstDateSurpassed: time.Now()}).Validate(), ErrResetWithUsageState)
+	c.ErrorIs((&UsageStateUpdate{ApplicationID: "app", ThresholdsReached: []UsageThreshold{"eighth"}}).Validate(), ErrInvalidUsageThreshold)
+}